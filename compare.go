@@ -0,0 +1,405 @@
+package setting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/constraint"
+)
+
+// ChangeKind is a kind of difference between two versions of a group schema.
+type ChangeKind int
+
+const (
+	// ChangeAdded indicates a new element, subgroup, list or map
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved indicates a removed element, subgroup, list or map
+	ChangeRemoved
+	// ChangeTypeChanged indicates a different element value type
+	ChangeTypeChanged
+	// ChangeSliceChanged indicates an element changed between slice and single value
+	ChangeSliceChanged
+	// ChangeRequired indicates an element that is now required, or a subgroup
+	// that is no longer optional
+	ChangeRequired
+	// ChangeNotRequired indicates an element that is no longer required, or a
+	// subgroup that is now optional
+	ChangeNotRequired
+	// ChangeConstraintAdded indicates a new constraint
+	ChangeConstraintAdded
+	// ChangeConstraintRemoved indicates a removed constraint
+	ChangeConstraintRemoved
+	// ChangeConstraintTightened indicates a constraint that allows fewer values
+	ChangeConstraintTightened
+	// ChangeConstraintLoosened indicates a constraint that allows more values
+	ChangeConstraintLoosened
+	// ChangeConstraintChanged indicates a constraint that allows different values
+	ChangeConstraintChanged
+	// ChangeItemCount indicates a change to the minimum or maximum number of list items
+	ChangeItemCount
+)
+
+// String returns a readable name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeTypeChanged:
+		return "type changed"
+	case ChangeSliceChanged:
+		return "slice changed"
+	case ChangeRequired:
+		return "now required"
+	case ChangeNotRequired:
+		return "no longer required"
+	case ChangeConstraintAdded:
+		return "constraint added"
+	case ChangeConstraintRemoved:
+		return "constraint removed"
+	case ChangeConstraintTightened:
+		return "constraint tightened"
+	case ChangeConstraintLoosened:
+		return "constraint loosened"
+	case ChangeConstraintChanged:
+		return "constraint changed"
+	case ChangeItemCount:
+		return "item count changed"
+	}
+
+	return ""
+}
+
+// SchemaChange is a difference between two versions of a group schema,
+// found by CompareSchemas.
+type SchemaChange struct {
+	// Path is the setting path, with names such as "upstreams[N]" for list
+	// items and "<key>" for map keys
+	Path []string
+	Kind ChangeKind
+	// Breaking changes can make a configuration that was valid become invalid
+	Breaking bool
+	// Old and New describe what changed, such as the constraints or value types,
+	// and are empty if not applicable
+	Old string
+	New string
+}
+
+// String returns a readable description of the change.
+func (c *SchemaChange) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s: %s", strings.Join(c.Path, "."), c.Kind)
+
+	switch {
+	case c.Old != "" && c.New != "":
+		fmt.Fprintf(&b, " from %s to %s", c.Old, c.New)
+	case c.Old != "":
+		fmt.Fprintf(&b, " %s", c.Old)
+	case c.New != "":
+		fmt.Fprintf(&b, " %s", c.New)
+	}
+
+	if c.Breaking {
+		b.WriteString(" (breaking)")
+	}
+
+	return b.String()
+}
+
+// CompareSchemas finds the differences between two versions of a group schema,
+// to tell whether configurations that were valid for the old version are still
+// valid for the new version. Breaking changes are removed elements, changed
+// value types, new required elements (or lists with a minimum number of items),
+// optional subgroups that are now required, and new or tightened constraints.
+// Removed and loosened constraints, and new elements that are not required,
+// are not breaking. Constraints are compared using constraint.Implies, and
+// changes that are not known to loosen a constraint are treated as breaking.
+// List items and map entries are not compared, only their templates.
+// Returns a non-nil error if constraints could not be compared.
+func CompareSchemas(from, to *Group) ([]*SchemaChange, error) {
+	changes := []*SchemaChange{}
+
+	if err := compareGroups([]string{}, from, to, &changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// BreakingChanges returns only the breaking changes.
+func BreakingChanges(changes []*SchemaChange) []*SchemaChange {
+	breaking := []*SchemaChange{}
+
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+
+	return breaking
+}
+
+func compareGroups(path []string, from, to *Group, changes *[]*SchemaChange) error {
+	names := from.Names()
+	for _, name := range to.Names() {
+		if !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		namePath := appendPath(path, name)
+
+		fromElem, toElem := from.Elements[name], to.Elements[name]
+		if fromElem != nil && toElem != nil {
+			if err := compareElements(namePath, fromElem, toElem, changes); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fromGroup, toGroup := from.Subgroups[name], to.Subgroups[name]
+		if fromGroup != nil && toGroup != nil {
+			// a configuration could leave out an optional subgroup, or give it as null
+			switch {
+			case fromGroup.Optional && !toGroup.Optional:
+				*changes = append(*changes, &SchemaChange{Path: namePath, Kind: ChangeRequired, Breaking: true})
+			case !fromGroup.Optional && toGroup.Optional:
+				*changes = append(*changes, &SchemaChange{Path: namePath, Kind: ChangeNotRequired})
+			}
+
+			if err := compareGroups(namePath, fromGroup, toGroup, changes); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fromList, toList := from.Lists[name], to.Lists[name]
+		if fromList != nil && toList != nil {
+			if err := compareLists(path, name, fromList, toList, changes); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fromMap, toMap := from.Maps[name], to.Maps[name]
+		if fromMap != nil && toMap != nil {
+			err := compareConstraints(namePath, fromMap.KeyConstraints, toMap.KeyConstraints, changes)
+			if err != nil {
+				return err
+			}
+
+			if err = compareGroups(appendPath(namePath, "<key>"), fromMap.Template, toMap.Template, changes); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		// the name is new, removed, or used for a different kind of setting
+		if from.hasName(name) {
+			*changes = append(*changes, &SchemaChange{Path: namePath, Kind: ChangeRemoved, Breaking: true})
+		}
+
+		if to.hasName(name) {
+			*changes = append(*changes, &SchemaChange{
+				Path: namePath, Kind: ChangeAdded, Breaking: to.needsName(name)})
+		}
+	}
+
+	return nil
+}
+
+func compareLists(path []string, name string, from, to *List, changes *[]*SchemaChange) error {
+	if from.MinItems != to.MinItems || from.MaxItems != to.MaxItems {
+		tighter := to.MinItems > from.MinItems ||
+			(to.MaxItems != 0 && (from.MaxItems == 0 || to.MaxItems < from.MaxItems))
+
+		*changes = append(*changes, &SchemaChange{
+			Path:     appendPath(path, name),
+			Kind:     ChangeItemCount,
+			Breaking: tighter,
+			Old:      itemCountString(from),
+			New:      itemCountString(to),
+		})
+	}
+
+	return compareGroups(appendPath(path, name+"[N]"), from.Template, to.Template, changes)
+}
+
+func itemCountString(l *List) string {
+	if l.MaxItems == 0 {
+		return fmt.Sprintf("%d..", l.MinItems)
+	}
+
+	return fmt.Sprintf("%d..%d", l.MinItems, l.MaxItems)
+}
+
+func compareElements(path []string, from, to *Element, changes *[]*SchemaChange) error {
+	if from.Value.IsSlice() != to.Value.IsSlice() {
+		*changes = append(*changes, &SchemaChange{
+			Path:     path,
+			Kind:     ChangeSliceChanged,
+			Breaking: true,
+			Old:      typeName(from.Value),
+			New:      typeName(to.Value),
+		})
+
+		return nil
+	}
+
+	if from.Value.Type() != to.Value.Type() {
+		*changes = append(*changes, &SchemaChange{
+			Path:     path,
+			Kind:     ChangeTypeChanged,
+			Breaking: true,
+			Old:      typeName(from.Value),
+			New:      typeName(to.Value),
+		})
+
+		return nil
+	}
+
+	switch {
+	case to.Required && !from.Required:
+		*changes = append(*changes, &SchemaChange{Path: path, Kind: ChangeRequired, Breaking: true})
+	case from.Required && !to.Required:
+		*changes = append(*changes, &SchemaChange{Path: path, Kind: ChangeNotRequired})
+	}
+
+	return compareConstraints(path, from.Constraints, to.Constraints, changes)
+}
+
+// compareConstraints pairs constraints of the same kind, such as upper bounds,
+// and finds whether each pair is tightened or loosened. Unpaired constraints
+// are added or removed. An added constraint is not breaking if it is implied
+// by the old constraints.
+func compareConstraints(path []string, from, to []constraint.Constraint, changes *[]*SchemaChange) error {
+	paired := make([]bool, len(to))
+
+	for _, c1 := range from {
+		i := pairConstraint(c1, to, paired)
+		if i < 0 {
+			*changes = append(*changes, &SchemaChange{
+				Path: path, Kind: ChangeConstraintRemoved, Old: c1.String()})
+
+			continue
+		}
+
+		paired[i] = true
+		c2 := to[i]
+
+		loosened, err := constraint.Implies(c1, c2)
+		if err != nil {
+			return annotateError(err, path, false)
+		}
+
+		tightened, err := constraint.Implies(c2, c1)
+		if err != nil {
+			return annotateError(err, path, false)
+		}
+
+		var kind ChangeKind
+
+		switch {
+		case loosened && tightened:
+			continue
+		case loosened:
+			kind = ChangeConstraintLoosened
+		case tightened:
+			kind = ChangeConstraintTightened
+		default:
+			kind = ChangeConstraintChanged
+		}
+
+		*changes = append(*changes, &SchemaChange{
+			Path:     path,
+			Kind:     kind,
+			Breaking: kind != ChangeConstraintLoosened,
+			Old:      c1.String(),
+			New:      c2.String(),
+		})
+	}
+
+	for i, c2 := range to {
+		if paired[i] {
+			continue
+		}
+
+		implied := false
+
+		for _, c1 := range from {
+			ok, err := constraint.Implies(c1, c2)
+			if err != nil {
+				return annotateError(err, path, false)
+			}
+
+			implied = implied || ok
+		}
+
+		*changes = append(*changes, &SchemaChange{
+			Path: path, Kind: ChangeConstraintAdded, Breaking: !implied, New: c2.String()})
+	}
+
+	return nil
+}
+
+// pairConstraint returns the index of the first unpaired constraint of the
+// same kind, or -1 if there is none. Lower and upper bounds are the same kind
+// regardless of being inclusive, and Func constraints must have the same name.
+func pairConstraint(c constraint.Constraint, others []constraint.Constraint, paired []bool) int {
+	for i, other := range others {
+		if !paired[i] && constraintKind(c) == constraintKind(other) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func constraintKind(c constraint.Constraint) string {
+	switch c.Type() {
+	case constraint.TypeGreater, constraint.TypeGreaterEqual:
+		return "lower"
+	case constraint.TypeLess, constraint.TypeLessEqual:
+		return "upper"
+	case constraint.TypeFunc:
+		return constraint.FuncStr + ":" + c.(*constraint.Func).Name()
+	}
+
+	return c.Type().String()
+}
+
+func (g *Group) hasName(name string) bool {
+	return g.Elements[name] != nil || g.Subgroups[name] != nil || g.Lists[name] != nil || g.Maps[name] != nil
+}
+
+// needsName returns true if a configuration must give a value for the
+// element, subgroup or list with the name.
+func (g *Group) needsName(name string) bool {
+	if elem, found := g.Elements[name]; found {
+		return elem.Required
+	}
+
+	if list, found := g.Lists[name]; found {
+		return list.MinItems > 0
+	}
+
+	subgroup, found := g.Subgroups[name]
+	if !found || subgroup.Optional {
+		return false
+	}
+
+	for _, subname := range subgroup.Names() {
+		if subgroup.needsName(subname) {
+			return true
+		}
+	}
+
+	return false
+}
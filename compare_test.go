@@ -0,0 +1,192 @@
+package setting_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCompareGroup() *setting.Group {
+	return &setting.Group{
+		Elements: map[string]*setting.Element{
+			"port": setting.NewElement(value.NewInt(8080),
+				constraint.NewGreater(value.NewInt(0)),
+				constraint.NewLessEqual(value.NewInt(65535))),
+			"level": setting.NewElement(value.NewString("info"),
+				constraint.NewOneOf(value.NewStringSlice("debug", "info", "warn"))),
+			"timeout": setting.NewElement(value.NewUInt(30)),
+			"hosts":   setting.NewElement(value.NewStringSlice()),
+			"name":    setting.NewElement(value.NewString("")),
+		},
+		Lists: map[string]*setting.List{
+			"upstreams": setting.NewList(&setting.Group{
+				Elements: map[string]*setting.Element{
+					"weight": setting.NewElement(value.NewInt(1), constraint.NewMultipleOf(value.NewInt(2))),
+				},
+			}, 0, 4),
+		},
+	}
+}
+
+func TestCompareSchemasSame(t *testing.T) {
+	changes, err := setting.CompareSchemas(newTestCompareGroup(), newTestCompareGroup())
+
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestCompareSchemas(t *testing.T) {
+	from := newTestCompareGroup()
+	to := newTestCompareGroup()
+
+	to.Elements["port"].Constraints = []constraint.Constraint{
+		constraint.NewGreaterEqual(value.NewInt(0)),
+		constraint.NewLessEqual(value.NewInt(1024)),
+	}
+	to.Elements["level"].Constraints = []constraint.Constraint{
+		constraint.NewOneOf(value.NewStringSlice("debug", "info")),
+		constraint.NewMinLen(1),
+	}
+	to.Elements["timeout"].Value = value.NewInt(30)
+	to.Elements["hosts"].Value = value.NewString("")
+	delete(to.Elements, "name")
+	to.Elements["region"] = setting.NewElement(value.NewString("eu"))
+	to.Elements["zone"] = setting.NewElement(value.NewString(""))
+	to.Elements["zone"].Required = true
+	to.Lists["upstreams"].MinItems = 1
+	to.Lists["upstreams"].Template.Elements["weight"].Constraints = []constraint.Constraint{
+		constraint.NewMultipleOf(value.NewInt(4)),
+	}
+	to.Subgroups = map[string]*setting.Group{
+		"tls": {
+			Elements: map[string]*setting.Element{"cert": to.Elements["zone"]},
+			Optional: true,
+		},
+	}
+
+	changes, err := setting.CompareSchemas(from, to)
+
+	assert.NoError(t, err)
+
+	strs := make([]string, len(changes))
+	for i, change := range changes {
+		strs[i] = change.String()
+	}
+
+	assert.Equal(t, []string{
+		"hosts: slice changed from []string to string (breaking)",
+		"level: constraint tightened from x in [debug, info, warn] to x in [debug, info] (breaking)",
+		"level: constraint added len(x) >= 1",
+		"name: removed (breaking)",
+		"port: constraint loosened from x > 0 to x >= 0",
+		"port: constraint tightened from x <= 65535 to x <= 1024 (breaking)",
+		"timeout: type changed from uint64 to int64 (breaking)",
+		"upstreams: item count changed from 0..4 to 1..4 (breaking)",
+		"upstreams[N].weight: constraint tightened from x % 2 == 0 to x % 4 == 0 (breaking)",
+		"region: added",
+		"zone: added (breaking)",
+		"tls: added",
+	}, strs)
+
+	assert.Len(t, setting.BreakingChanges(changes), 8)
+	assert.Equal(t, setting.ChangeConstraintTightened, changes[1].Kind)
+	assert.Equal(t, []string{"upstreams[N]", "weight"}, changes[8].Path)
+}
+
+func TestCompareSchemasConstraints(t *testing.T) {
+	testCases := []struct {
+		from, to []constraint.Constraint
+		kinds    []setting.ChangeKind
+		breaking bool
+	}{
+		{
+			from:     []constraint.Constraint{constraint.NewLess(value.NewInt(10))},
+			to:       []constraint.Constraint{},
+			kinds:    []setting.ChangeKind{setting.ChangeConstraintRemoved},
+			breaking: false,
+		},
+		{
+			from:     []constraint.Constraint{constraint.NewOneOf(value.NewIntSlice(1, 2))},
+			to:       []constraint.Constraint{constraint.NewOneOf(value.NewIntSlice(2, 3))},
+			kinds:    []setting.ChangeKind{setting.ChangeConstraintChanged},
+			breaking: true,
+		},
+		{
+			from: []constraint.Constraint{constraint.NewOneOf(value.NewIntSlice(1, 2))},
+			to: []constraint.Constraint{
+				constraint.NewOneOf(value.NewIntSlice(1, 2, 3)),
+				constraint.NewLessEqual(value.NewInt(3)),
+			},
+			kinds:    []setting.ChangeKind{setting.ChangeConstraintLoosened, setting.ChangeConstraintAdded},
+			breaking: false,
+		},
+		{
+			from:     []constraint.Constraint{},
+			to:       []constraint.Constraint{constraint.NewGreater(value.NewInt(0))},
+			kinds:    []setting.ChangeKind{setting.ChangeConstraintAdded},
+			breaking: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		from := &setting.Group{Elements: map[string]*setting.Element{
+			"x": setting.NewElement(value.NewInt(1), tc.from...)}}
+		to := &setting.Group{Elements: map[string]*setting.Element{
+			"x": setting.NewElement(value.NewInt(1), tc.to...)}}
+
+		changes, err := setting.CompareSchemas(from, to)
+
+		assert.NoError(t, err)
+
+		kinds := []setting.ChangeKind{}
+		for _, change := range changes {
+			kinds = append(kinds, change.Kind)
+		}
+
+		assert.Equal(t, tc.kinds, kinds)
+		assert.Equal(t, tc.breaking, len(setting.BreakingChanges(changes)) > 0)
+	}
+}
+
+func TestCompareSchemasOptional(t *testing.T) {
+	from := newTestCompareGroup()
+	from.Subgroups = map[string]*setting.Group{"tls": {Optional: true}}
+	to := newTestCompareGroup()
+	to.Subgroups = map[string]*setting.Group{"tls": {}}
+
+	changes, err := setting.CompareSchemas(from, to)
+
+	assert.NoError(t, err)
+
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, "tls: now required (breaking)", changes[0].String())
+	}
+
+	changes, err = setting.CompareSchemas(to, from)
+
+	assert.NoError(t, err)
+
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, setting.ChangeNotRequired, changes[0].Kind)
+		assert.False(t, changes[0].Breaking)
+	}
+}
+
+func TestCompareSchemasMarshaled(t *testing.T) {
+	from := newTestCompareGroup()
+	data, err := from.MarshalSchema(setting.SchemaJSON)
+
+	assert.NoError(t, err)
+
+	old, err := setting.UnmarshalSchema(data, setting.SchemaJSON)
+
+	assert.NoError(t, err)
+
+	changes, err := setting.CompareSchemas(old, newTestCompareGroup())
+
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
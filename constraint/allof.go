@@ -0,0 +1,88 @@
+package constraint
+
+import (
+	"strings"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// AllOf restricts a value to satisfy all of the wrapped constraints
+type AllOf struct {
+	constraints []Constraint
+}
+
+// NewAllOf makes a new AllOf constraint
+func NewAllOf(constraints ...Constraint) *AllOf {
+	return &AllOf{constraints: constraints}
+}
+
+// Type returns the constraint type.
+func (c *AllOf) Type() Type { return TypeAllOf }
+
+// Param returns nil, since the wrapped constraints have their own parameters.
+func (c *AllOf) Param() value.Value { return nil }
+
+// Constraints returns the wrapped constraints.
+func (c *AllOf) Constraints() []Constraint { return c.constraints }
+
+// CompatibleWith returns true if the given constraint is compatible with
+// every wrapped constraint. Another AllOf is compared using its wrapped constraints.
+// Returns a non-nil error in case of failure.
+func (c *AllOf) CompatibleWith(c2 Constraint) (bool, error) {
+	others := []Constraint{c2}
+	if all, ok := c2.(*AllOf); ok {
+		others = all.constraints
+	}
+
+	for _, c1 := range c.constraints {
+		for _, other := range others {
+			compatible, err := Compatible(c1, other)
+			if err != nil || !compatible {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// compatibleWrapped returns true if the wrapped constraints are compatible
+// with each other, as in Compatible and CompatibleSet.
+// Returns a non-nil error in case of failure.
+func (c *AllOf) compatibleWrapped() (bool, error) {
+	for i, c1 := range c.constraints {
+		for _, c2 := range c.constraints[i+1:] {
+			compatible, err := Compatible(c1, c2)
+			if err != nil || !compatible {
+				return false, err
+			}
+		}
+	}
+
+	return CompatibleSet(c.constraints)
+}
+
+// Check returns the first error from checking the wrapped constraints.
+func (c *AllOf) Check(v value.Value) error {
+	for _, c2 := range c.constraints {
+		if err := c2.Check(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *AllOf) String() string {
+	if len(c.constraints) == 0 {
+		return "true"
+	}
+
+	strs := make([]string, len(c.constraints))
+	for i, c2 := range c.constraints {
+		strs[i] = formatOperand(c2)
+	}
+
+	return strings.Join(strs, " && ")
+}
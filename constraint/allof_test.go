@@ -0,0 +1,77 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllOf(t *testing.T) {
+	c := constraint.NewAllOf(
+		constraint.NewGreater(value.NewInt(0)),
+		constraint.NewLess(value.NewInt(10)))
+
+	assert.Equal(t, c.Type(), constraint.TypeAllOf)
+	assert.Nil(t, c.Param())
+	assert.Len(t, c.Constraints(), 2)
+	assert.Equal(t, "x > 0 && x < 10", c.String())
+
+	compatible := []constraint.Constraint{
+		constraint.NewMaxLen(0),
+		constraint.NewNot(constraint.NewOneOf(value.NewIntSlice(5))),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewGreater(value.NewInt(5)),
+		constraint.NewLessEqual(value.NewInt(-1)),
+		constraint.NewAllOf(constraint.NewLess(value.NewInt(5))),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+}
+
+func TestAllOfWrappedCompatible(t *testing.T) {
+	testCases := []struct {
+		c          *constraint.AllOf
+		compatible bool
+	}{
+		{constraint.NewAllOf(
+			constraint.NewGreater(value.NewInt(0)), constraint.NewLess(value.NewInt(10))), true},
+		{constraint.NewAllOf(
+			constraint.NewGreater(value.NewInt(10)), constraint.NewLess(value.NewInt(0))), false},
+		{constraint.NewAllOf(constraint.NewAllOf(
+			constraint.NewOneOf(value.NewIntSlice(1, 2)), constraint.NewGreater(value.NewInt(5)))), false},
+		{constraint.NewAllOf(
+			constraint.NewMultipleOf(value.NewInt(10)),
+			constraint.NewGreater(value.NewInt(1)), constraint.NewLess(value.NewInt(9))), false},
+	}
+
+	for _, tc := range testCases {
+		compatible, err := constraint.CompatibleSet([]constraint.Constraint{tc.c})
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.compatible, compatible, tc.c.String())
+	}
+}
+
+func TestAllOfCheck(t *testing.T) {
+	c := constraint.NewAllOf(
+		constraint.NewGreater(value.NewInt(0)),
+		constraint.NewLess(value.NewInt(10)))
+
+	assert.NoError(t, c.Check(value.NewInt(5)))
+	assert.Error(t, c.Check(value.NewInt(0)))
+	assert.Error(t, c.Check(value.NewInt(10)))
+	assert.NoError(t, constraint.NewAllOf().Check(value.NewInt(0)))
+}
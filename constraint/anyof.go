@@ -0,0 +1,76 @@
+package constraint
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// AnyOf restricts a value to satisfy at least one of the wrapped constraints
+type AnyOf struct {
+	constraints []Constraint
+}
+
+// NewAnyOf makes a new AnyOf constraint
+func NewAnyOf(constraints ...Constraint) *AnyOf {
+	return &AnyOf{constraints: constraints}
+}
+
+// Type returns the constraint type.
+func (c *AnyOf) Type() Type { return TypeAnyOf }
+
+// Param returns nil, since the wrapped constraints have their own parameters.
+func (c *AnyOf) Param() value.Value { return nil }
+
+// Constraints returns the wrapped constraints.
+func (c *AnyOf) Constraints() []Constraint { return c.constraints }
+
+// CompatibleWith returns true if the given constraint is compatible with
+// at least one of the wrapped constraints.
+// Returns a non-nil error in case of failure.
+func (c *AnyOf) CompatibleWith(c2 Constraint) (bool, error) {
+	for _, c1 := range c.constraints {
+		compatible, err := Compatible(c1, c2)
+		if err != nil {
+			return false, err
+		}
+
+		if compatible {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Check returns a non-nil error if none of the wrapped constraints are satisfied,
+// or if one fails for a reason other than a violation (such as a type mismatch).
+func (c *AnyOf) Check(v value.Value) error {
+	for _, c2 := range c.constraints {
+		err := c2.Check(v)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, ErrViolation) {
+			return err
+		}
+	}
+
+	return violation(c, v)
+}
+
+// String returns a readable description of the constraint.
+func (c *AnyOf) String() string {
+	if len(c.constraints) == 0 {
+		return "false"
+	}
+
+	strs := make([]string, len(c.constraints))
+	for i, c2 := range c.constraints {
+		strs[i] = formatOperand(c2)
+	}
+
+	return strings.Join(strs, " || ")
+}
@@ -0,0 +1,62 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnyOf(t *testing.T) {
+	c := constraint.NewAnyOf(
+		constraint.NewLess(value.NewInt(10)),
+		constraint.NewGreater(value.NewInt(100)))
+
+	assert.Equal(t, c.Type(), constraint.TypeAnyOf)
+	assert.Nil(t, c.Param())
+	assert.Len(t, c.Constraints(), 2)
+	assert.Equal(t, "x < 10 || x > 100", c.String())
+
+	compatible := []constraint.Constraint{
+		constraint.NewMinLen(0),
+		constraint.NewGreaterEqual(value.NewInt(0)),
+		constraint.NewLessEqual(value.NewInt(200)),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewOneOf(value.NewIntSlice(1, 2)),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+}
+
+func TestAnyOfCheck(t *testing.T) {
+	c := constraint.NewAnyOf(
+		constraint.NewLess(value.NewInt(10)),
+		constraint.NewGreater(value.NewInt(100)))
+
+	assert.NoError(t, c.Check(value.NewInt(5)))
+	assert.NoError(t, c.Check(value.NewInt(101)))
+	assert.Error(t, c.Check(value.NewInt(50)))
+	assert.Error(t, constraint.NewAnyOf().Check(value.NewInt(0)))
+}
+
+func TestAnyOfNestedString(t *testing.T) {
+	c := constraint.NewAnyOf(
+		constraint.NewAllOf(
+			constraint.NewGreater(value.NewInt(0)),
+			constraint.NewLess(value.NewInt(10))),
+		constraint.NewNot(constraint.NewLessEqual(value.NewInt(100))))
+
+	assert.Equal(t, "(x > 0 && x < 10) || !(x <= 100)", c.String())
+}
@@ -13,4 +13,96 @@ type Constraint interface {
 	// CompatibleWith returns true if the given constraint is compatible with the current one.
 	// Returns a non-nil error in case of failure.
 	CompatibleWith(Constraint) (bool, error)
+	// Check returns a non-nil error if the given value does not satisfy the constraint.
+	Check(value.Value) error
+	// String returns a readable description of the constraint, using x for the value.
+	String() string
+}
+
+// Composite is a constraint that is made from other constraints.
+type Composite interface {
+	Constraint
+	// Constraints returns the wrapped constraints.
+	Constraints() []Constraint
+}
+
+// ApplicableTo returns true if the constraint is applicable to the given value.
+// For a composite, each of the wrapped constraints must also be applicable
+// (to the slice elements, in the case of Each). For a Func, the value must
+// have one of the declared types.
+func ApplicableTo(c Constraint, v value.Value) bool {
+	if !c.Type().ApplicableTo(v) {
+		return false
+	}
+
+	if f, ok := c.(*Func); ok {
+		return f.ApplicableTo(v)
+	}
+
+	if comp, ok := c.(Composite); ok {
+		// constraints wrapped by Each apply to the slice elements
+		if c.Type() == TypeEach {
+			v = value.NewSingle(v.Type())
+		}
+
+		for _, c2 := range comp.Constraints() {
+			if !ApplicableTo(c2, v) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Compatible returns true if each of the given constraints is compatible
+// with the other.
+// Returns a non-nil error in case of failure.
+func Compatible(c1, c2 Constraint) (bool, error) {
+	compatible, err := c1.CompatibleWith(c2)
+	if err != nil || !compatible {
+		return false, err
+	}
+
+	return c2.CompatibleWith(c1)
+}
+
+// CompatibleSet returns true if the given constraints can be satisfied together
+// in ways that pairwise compatibility does not cover. Currently, this checks
+// that a MultipleOf leaves at least one valid value between lower and upper bounds,
+// and that the constraints wrapped by each AllOf are compatible with each other.
+// Returns a non-nil error in case of failure.
+func CompatibleSet(constraints []Constraint) (bool, error) {
+	for _, c := range constraints {
+		if all, ok := c.(*AllOf); ok {
+			compatible, err := all.compatibleWrapped()
+			if err != nil || !compatible {
+				return false, err
+			}
+		}
+	}
+
+	var lower, upper Constraint
+
+	multiples := []*MultipleOf{}
+
+	for _, c := range constraints {
+		switch c.Type() {
+		case TypeGreater, TypeGreaterEqual:
+			lower = c
+		case TypeLess, TypeLessEqual:
+			upper = c
+		case TypeMultipleOf:
+			multiples = append(multiples, c.(*MultipleOf))
+		}
+	}
+
+	for _, m := range multiples {
+		ok, err := m.hasMultipleWithin(lower, upper)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
 }
@@ -0,0 +1,63 @@
+package constraint
+
+import "github.com/jamestunnell/go-setting/value"
+
+// Contains restricts a slice value to include the parameter value
+type Contains struct {
+	val value.Single
+}
+
+// NewContains makes a new Contains constraint
+func NewContains(val value.Single) *Contains {
+	return &Contains{val: val}
+}
+
+// Type returns the constraint type.
+func (c *Contains) Type() Type { return TypeContains }
+
+// Param returns the constraint parameter.
+func (c *Contains) Param() value.Value { return c.val }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// Constraints that apply to every slice element are incompatible if the required
+// value would not satisfy them. A zero MaxLen is also incompatible.
+// Returns a non-nil error in case of failure.
+func (c *Contains) CompatibleWith(c2 Constraint) (bool, error) {
+	switch c2.Type() {
+	case TypeGreater, TypeGreaterEqual, TypeLess, TypeLessEqual, TypeNoneOf:
+		return satisfies(c2, c.val)
+	case TypeEach:
+		for _, c3 := range c2.(Composite).Constraints() {
+			ok, err := satisfies(c3, c.val)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	case TypeMaxLen:
+		return c2.Param().(value.Single).Greater(value.NewUInt(0))
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error if the slice value does not include the parameter value.
+func (c *Contains) Check(v value.Value) error {
+	slice, ok := v.(value.Slice)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	found, err := slice.Contains(c.val)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return violation(c, v)
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *Contains) String() string { return FormatValue(c.val) + " in x" }
@@ -0,0 +1,56 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContains(t *testing.T) {
+	c := constraint.NewContains(value.NewString("us"))
+
+	assert.Equal(t, c.Type(), constraint.TypeContains)
+	assert.Equal(t, "us in x", c.String())
+
+	compatible := []constraint.Constraint{
+		constraint.NewContains(value.NewString("eu")),
+		constraint.NewMaxLen(1),
+		constraint.NewUnique(),
+		constraint.NewEach(constraint.NewOneOf(value.NewStringSlice("us", "eu"))),
+		constraint.NewNoneOf(value.NewStringSlice("ap")),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewMaxLen(0),
+		constraint.NewEach(constraint.NewOneOf(value.NewStringSlice("eu"))),
+		constraint.NewNoneOf(value.NewStringSlice("us")),
+		constraint.NewGreater(value.NewString("zz")),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+
+	_, err := c.CompatibleWith(constraint.NewLess(value.NewInt(5)))
+
+	assert.Error(t, err)
+}
+
+func TestContainsCheck(t *testing.T) {
+	c := constraint.NewContains(value.NewString("us"))
+
+	assert.NoError(t, c.Check(value.NewStringSlice("eu", "us")))
+	assert.Error(t, c.Check(value.NewStringSlice("eu")))
+	assert.Error(t, c.Check(value.NewStringSlice()))
+	assert.Error(t, c.Check(value.NewIntSlice(1)))
+	assert.Error(t, c.Check(value.NewString("us")))
+}
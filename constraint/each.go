@@ -0,0 +1,95 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// Each restricts every element of a slice value to satisfy all of the
+// wrapped single-value constraints
+type Each struct {
+	constraints []Constraint
+}
+
+// NewEach makes a new Each constraint
+func NewEach(constraints ...Constraint) *Each {
+	return &Each{constraints: constraints}
+}
+
+// Type returns the constraint type.
+func (c *Each) Type() Type { return TypeEach }
+
+// Param returns nil, since the wrapped constraints have their own parameters.
+func (c *Each) Param() value.Value { return nil }
+
+// Constraints returns the wrapped constraints.
+func (c *Each) Constraints() []Constraint { return c.constraints }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// Another Each, or a constraint that also applies to every slice element
+// (comparisons and NoneOf), is compared against each wrapped constraint.
+// Returns a non-nil error in case of failure.
+func (c *Each) CompatibleWith(c2 Constraint) (bool, error) {
+	var others []Constraint
+
+	switch c2.Type() {
+	case TypeEach:
+		others = c2.(Composite).Constraints()
+	case TypeGreater, TypeGreaterEqual, TypeLess, TypeLessEqual, TypeNoneOf:
+		others = []Constraint{c2}
+	}
+
+	for _, c1 := range c.constraints {
+		for _, other := range others {
+			compatible, err := Compatible(c1, other)
+			if err != nil || !compatible {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error for the first slice element that does not
+// satisfy a wrapped constraint.
+func (c *Each) Check(v value.Value) error {
+	slice, ok := v.(value.Slice)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+
+		for _, c2 := range c.constraints {
+			if err := c2.Check(elem); err != nil {
+				if !errors.Is(err, ErrViolation) {
+					return err
+				}
+
+				return &ViolationError{
+					Constraint: c,
+					Value:      v,
+					Reason:     fmt.Sprintf("element %d", i),
+					Err:        err,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *Each) String() string {
+	strs := make([]string, len(c.constraints))
+	for i, c2 := range c.constraints {
+		strs[i] = formatOperand(c2)
+	}
+
+	return "each(" + strings.Join(strs, " && ") + ")"
+}
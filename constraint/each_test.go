@@ -0,0 +1,67 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEach(t *testing.T) {
+	c := constraint.NewEach(
+		constraint.NewGreater(value.NewInt(0)),
+		constraint.NewLess(value.NewInt(10)))
+
+	assert.Equal(t, c.Type(), constraint.TypeEach)
+	assert.Nil(t, c.Param())
+	assert.Len(t, c.Constraints(), 2)
+	assert.Equal(t, "each(x > 0 && x < 10)", c.String())
+
+	compatible := []constraint.Constraint{
+		constraint.NewMaxLen(3),
+		constraint.NewUnique(),
+		constraint.NewNoneOf(value.NewIntSlice(5)),
+		constraint.NewEach(constraint.NewNoneOf(value.NewIntSlice(3))),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewLessEqual(value.NewInt(0)),
+		constraint.NewGreater(value.NewInt(-5)),
+		constraint.NewEach(constraint.NewGreaterEqual(value.NewInt(10))),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+}
+
+func TestEachCheck(t *testing.T) {
+	c := constraint.NewEach(
+		constraint.NewOneOf(value.NewStringSlice("us", "eu", "ap")))
+
+	assert.NoError(t, c.Check(value.NewStringSlice()))
+	assert.NoError(t, c.Check(value.NewStringSlice("us", "ap")))
+
+	err := c.Check(value.NewStringSlice("us", "mars"))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "element 1")
+	assert.Error(t, c.Check(value.NewString("us")))
+}
+
+func TestApplicableToWithEach(t *testing.T) {
+	c := constraint.NewEach(constraint.NewOneOf(value.NewIntSlice(1)))
+
+	assert.True(t, constraint.ApplicableTo(c, value.NewIntSlice()))
+	assert.False(t, constraint.ApplicableTo(c, value.NewInt(0)))
+	assert.False(t, constraint.ApplicableTo(
+		constraint.NewEach(constraint.NewMinLen(1)), value.NewIntSlice()))
+}
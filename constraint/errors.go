@@ -0,0 +1,160 @@
+package constraint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+const (
+	// CodeViolation is the error code for a ViolationError
+	CodeViolation = "constraint_violation"
+	// CodeNotApplicable is the error code for a NotApplicableError
+	CodeNotApplicable = "not_applicable"
+	// CodeIncompatibleConstraints is the error code for an IncompatibleConstraintsError
+	CodeIncompatibleConstraints = "incompatible_constraints"
+)
+
+var (
+	// ErrViolation matches any ViolationError using errors.Is
+	ErrViolation = errors.New("constraint violation")
+	// ErrNotApplicable matches any NotApplicableError using errors.Is
+	ErrNotApplicable = errors.New("constraint not applicable")
+	// ErrIncompatibleConstraints matches any IncompatibleConstraintsError using errors.Is
+	ErrIncompatibleConstraints = errors.New("incompatible constraints")
+)
+
+// ViolationError indicates that a value does not satisfy a constraint.
+type ViolationError struct {
+	// Path locates the setting, if known
+	Path       []string
+	Constraint Constraint
+	// Value is the value that failed, which may be a single slice element
+	Value value.Value
+	// Reason gives optional details
+	Reason string
+	// Err is an optional underlying error
+	Err error
+	// Redacted hides the value, reason and underlying error, for a sensitive value
+	Redacted bool
+}
+
+// NotApplicableError indicates that a constraint cannot be used with a value.
+type NotApplicableError struct {
+	// Path locates the setting, if known
+	Path       []string
+	Constraint Constraint
+	Value      value.Value
+	// Redacted hides the value, reason and underlying error, for a sensitive value
+	Redacted bool
+}
+
+// IncompatibleConstraintsError indicates constraints that cannot be used together.
+type IncompatibleConstraintsError struct {
+	// Path locates the setting, if known
+	Path        []string
+	Constraints []Constraint
+}
+
+// Error returns the error message.
+func (e *ViolationError) Error() string {
+	msg := fmt.Sprintf("value %s does not satisfy %s", formatRedacted(e.Value, e.Redacted), e.Constraint)
+
+	// the reason and underlying error could include the value
+	if e.Reason != "" && !e.Redacted {
+		msg += " (" + e.Reason + ")"
+	}
+
+	if e.Err != nil && !e.Redacted {
+		msg += ": " + e.Err.Error()
+	}
+
+	return withPath(e.Path, msg)
+}
+
+// Code returns CodeViolation.
+func (e *ViolationError) Code() string { return CodeViolation }
+
+// Is returns true for ErrViolation.
+func (e *ViolationError) Is(target error) bool { return target == ErrViolation }
+
+// Unwrap returns the underlying error, if any.
+func (e *ViolationError) Unwrap() error { return e.Err }
+
+// Error returns the error message.
+func (e *NotApplicableError) Error() string {
+	msg := fmt.Sprintf("constraint type %s is not applicable to %s value %s",
+		e.Constraint.Type(), formatType(e.Value), formatRedacted(e.Value, e.Redacted))
+
+	return withPath(e.Path, msg)
+}
+
+// Code returns CodeNotApplicable.
+func (e *NotApplicableError) Code() string { return CodeNotApplicable }
+
+// Is returns true for ErrNotApplicable.
+func (e *NotApplicableError) Is(target error) bool { return target == ErrNotApplicable }
+
+// Error returns the error message.
+func (e *IncompatibleConstraintsError) Error() string {
+	var msg string
+
+	if len(e.Constraints) == 2 {
+		msg = fmt.Sprintf("constraint %v is not compatible with %v",
+			e.Constraints[1], e.Constraints[0])
+	} else {
+		strs := make([]string, len(e.Constraints))
+		for i, c := range e.Constraints {
+			strs[i] = c.String()
+		}
+
+		msg = fmt.Sprintf("constraints %s cannot be satisfied together",
+			strings.Join(strs, ", "))
+	}
+
+	return withPath(e.Path, msg)
+}
+
+// Code returns CodeIncompatibleConstraints.
+func (e *IncompatibleConstraintsError) Code() string { return CodeIncompatibleConstraints }
+
+// Is returns true for ErrIncompatibleConstraints.
+func (e *IncompatibleConstraintsError) Is(target error) bool {
+	return target == ErrIncompatibleConstraints
+}
+
+// violation makes an error for a value that does not satisfy a constraint.
+func violation(c Constraint, v value.Value) error {
+	return &ViolationError{Constraint: c, Value: v}
+}
+
+// notApplicable makes an error for a value that a constraint cannot check.
+func notApplicable(c Constraint, v value.Value) error {
+	return &NotApplicableError{Constraint: c, Value: v}
+}
+
+func formatRedacted(v value.Value, redacted bool) string {
+	if redacted {
+		return value.Redacted
+	}
+
+	return FormatValue(v)
+}
+
+func formatType(v value.Value) string {
+	if v.IsSlice() {
+		return "[]" + v.Type().String()
+	}
+
+	return v.Type().String()
+}
+
+func withPath(path []string, msg string) string {
+	if len(path) == 0 {
+		return msg
+	}
+
+	return strings.Join(path, ".") + ": " + msg
+}
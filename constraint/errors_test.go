@@ -0,0 +1,77 @@
+package constraint_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViolationError(t *testing.T) {
+	c := constraint.NewLessEqual(value.NewInt(10))
+	err := c.Check(value.NewInt(11))
+
+	var violation *constraint.ViolationError
+
+	if assert.True(t, errors.As(err, &violation)) {
+		assert.Equal(t, c, violation.Constraint)
+		assert.Equal(t, int64(11), violation.Value.(value.Single).Value())
+		assert.Equal(t, constraint.CodeViolation, violation.Code())
+	}
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, "value 11 does not satisfy x <= 10", err.Error())
+
+	violation.Path = []string{"a", "b"}
+
+	assert.Equal(t, "a.b: value 11 does not satisfy x <= 10", err.Error())
+}
+
+func TestViolationErrorFromEach(t *testing.T) {
+	oneOf := constraint.NewOneOf(value.NewStringSlice("us", "eu"))
+	err := constraint.NewEach(oneOf).Check(value.NewStringSlice("us", "mars"))
+
+	var violation *constraint.ViolationError
+
+	assert.True(t, errors.As(err, &violation))
+	assert.Equal(t, "element 1", violation.Reason)
+	assert.True(t, errors.As(violation.Err, &violation))
+	assert.Equal(t, oneOf, violation.Constraint)
+	assert.Equal(t, "mars", violation.Value.(value.Single).Value())
+}
+
+func TestNotApplicableError(t *testing.T) {
+	err := constraint.NewUnique().Check(value.NewInt(1))
+
+	var notApplicable *constraint.NotApplicableError
+
+	if assert.True(t, errors.As(err, &notApplicable)) {
+		assert.Equal(t, constraint.CodeNotApplicable, notApplicable.Code())
+	}
+
+	assert.True(t, errors.Is(err, constraint.ErrNotApplicable))
+	assert.False(t, errors.Is(err, constraint.ErrViolation))
+}
+
+func TestIncompatibleConstraintsError(t *testing.T) {
+	c1 := constraint.NewLess(value.NewInt(1))
+	c2 := constraint.NewGreater(value.NewInt(5))
+	err := &constraint.IncompatibleConstraintsError{
+		Constraints: []constraint.Constraint{c1, c2},
+	}
+
+	assert.True(t, errors.Is(err, constraint.ErrIncompatibleConstraints))
+	assert.Equal(t, constraint.CodeIncompatibleConstraints, err.Code())
+	assert.Equal(t, "constraint x > 5 is not compatible with x < 1", err.Error())
+}
+
+func TestCompositeCheckPassesThroughTypeMismatch(t *testing.T) {
+	less := constraint.NewLess(value.NewInt(1))
+
+	assert.True(t, errors.Is(
+		constraint.NewNot(less).Check(value.NewFloat(0)), value.ErrTypeMismatch))
+	assert.True(t, errors.Is(
+		constraint.NewAnyOf(less).Check(value.NewFloat(0)), value.ErrTypeMismatch))
+}
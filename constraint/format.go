@@ -0,0 +1,88 @@
+package constraint
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// FormatValue makes a readable string from a single or slice value.
+// Slice values are comma-separated, inside square brackets.
+func FormatValue(v value.Value) string {
+	switch vv := v.(type) {
+	case value.Single:
+		return fmt.Sprint(vv.Value())
+	case value.Slice:
+		rv := reflect.ValueOf(vv.Slice())
+		strs := make([]string, rv.Len())
+
+		for i := 0; i < rv.Len(); i++ {
+			strs[i] = fmt.Sprint(rv.Index(i).Interface())
+		}
+
+		return "[" + strings.Join(strs, ", ") + "]"
+	}
+
+	return fmt.Sprint(v)
+}
+
+// formatOperand describes a constraint for use inside a composite
+// description, adding parentheses where needed to keep precedence clear.
+func formatOperand(c Constraint) string {
+	switch c.Type() {
+	case TypeAllOf, TypeAnyOf:
+		if len(c.(Composite).Constraints()) > 1 {
+			return "(" + c.String() + ")"
+		}
+	}
+
+	return c.String()
+}
+
+// checkCompare applies one of the comparison methods of the given value,
+// using the constraint parameter. An empty slice satisfies any comparison.
+func checkCompare(
+	c Constraint,
+	v value.Value,
+	compare func(value.Single) (bool, error),
+) error {
+	if s, ok := v.(value.Slice); ok && s.Len() == 0 {
+		return nil
+	}
+
+	ok, err := compare(c.Param().(value.Single))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return violation(c, v)
+	}
+
+	return nil
+}
+
+// checkLen applies a length comparison to a slice or string value.
+func checkLen(c Constraint, v value.Value, compare func(a, b uint64) bool) error {
+	var n uint64
+
+	switch vv := v.(type) {
+	case value.Slice:
+		n = uint64(vv.Len())
+	case value.Single:
+		str, ok := vv.Value().(string)
+		if !ok {
+			return notApplicable(c, v)
+		}
+
+		n = uint64(len(str))
+	}
+
+	if !compare(n, c.Param().(value.Single).Value().(uint64)) {
+		return violation(c, v)
+	}
+
+	return nil
+}
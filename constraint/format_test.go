@@ -0,0 +1,16 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "2.5", constraint.FormatValue(value.NewFloat(2.5)))
+	assert.Equal(t, "abc", constraint.FormatValue(value.NewString("abc")))
+	assert.Equal(t, "[]", constraint.FormatValue(value.NewIntSlice()))
+	assert.Equal(t, "[true, false]", constraint.FormatValue(value.NewBoolSlice(true, false)))
+}
@@ -0,0 +1,104 @@
+package constraint
+
+import (
+	"fmt"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// CheckFunc checks a value, returning a non-nil error if it is not valid.
+type CheckFunc func(value.Value) error
+
+// CompatibleFunc checks if a constraint is compatible with a Func constraint.
+type CompatibleFunc func(Constraint) (bool, error)
+
+// Func restricts a value using a custom check function. It is identified by
+// name, which is also the parameter, so that it can be exported.
+type Func struct {
+	name        string
+	description string
+	check       CheckFunc
+	compatible  CompatibleFunc
+	types       []value.Type
+}
+
+// NewFunc makes a new Func constraint that is compatible with all other constraints.
+// If no value types are given, then it is applicable to values of any type.
+// Panics if the check function is nil.
+func NewFunc(name, description string, check CheckFunc, types ...value.Type) *Func {
+	return NewFuncWithCompatibility(name, description, check, nil, types...)
+}
+
+// NewFuncWithCompatibility makes a new Func constraint that uses the given function
+// to check compatibility with other constraints.
+// If no value types are given, then it is applicable to values of any type.
+// Panics if the check function is nil.
+func NewFuncWithCompatibility(
+	name, description string,
+	check CheckFunc,
+	compatible CompatibleFunc,
+	types ...value.Type,
+) *Func {
+	if check == nil {
+		panic(fmt.Sprintf("constraint: func %q has a nil check function", name))
+	}
+
+	return &Func{
+		name:        name,
+		description: description,
+		check:       check,
+		compatible:  compatible,
+		types:       types,
+	}
+}
+
+// Type returns the constraint type.
+func (c *Func) Type() Type { return TypeFunc }
+
+// Param returns the constraint name as a string value.
+func (c *Func) Param() value.Value { return value.NewString(c.name) }
+
+// Name returns the constraint name.
+func (c *Func) Name() string { return c.name }
+
+// Description returns the constraint description.
+func (c *Func) Description() string { return c.description }
+
+// ApplicableTo returns true if the value type is one of the declared types,
+// or if no types were declared.
+func (c *Func) ApplicableTo(v value.Value) bool {
+	if len(c.types) == 0 {
+		return true
+	}
+
+	for _, t := range c.types {
+		if v.Type() == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CompatibleWith returns true if the given constraint is compatible with the current one,
+// using the compatibility function if there is one.
+// Returns a non-nil error in case of failure.
+func (c *Func) CompatibleWith(c2 Constraint) (bool, error) {
+	if c.compatible == nil {
+		return true, nil
+	}
+
+	return c.compatible(c2)
+}
+
+// Check returns a non-nil error if the check function fails.
+func (c *Func) Check(v value.Value) error {
+	if err := c.check(v); err != nil {
+		return &ViolationError{Constraint: c, Value: v, Err: err}
+	}
+
+	return nil
+}
+
+// String returns the constraint name.
+func (c *Func) String() string { return c.name }
@@ -0,0 +1,97 @@
+package constraint_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunc(t *testing.T) {
+	c := newLowercaseFunc()
+
+	assert.Equal(t, c.Type(), constraint.TypeFunc)
+	assert.Equal(t, "lowercase", c.Name())
+	assert.Equal(t, "must be all lowercase", c.Description())
+	assert.Equal(t, "lowercase", c.String())
+	assert.Equal(t, "lowercase", c.Param().(value.Single).Value())
+
+	for _, c2 := range []constraint.Constraint{
+		constraint.NewMaxLen(3),
+		constraint.NewOneOf(value.NewStringSlice("a")),
+		newLowercaseFunc(),
+	} {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+}
+
+func TestFuncWithCompatibility(t *testing.T) {
+	c := constraint.NewFuncWithCompatibility("even", "must be even",
+		func(v value.Value) error { return nil },
+		func(c2 constraint.Constraint) (bool, error) {
+			return c2.Type() != constraint.TypeMultipleOf, nil
+		},
+		value.TypeInt)
+
+	result, err := c.CompatibleWith(constraint.NewLess(value.NewInt(5)))
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	result, err = c.CompatibleWith(constraint.NewMultipleOf(value.NewInt(5)))
+
+	assert.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestFuncCheck(t *testing.T) {
+	c := newLowercaseFunc()
+
+	assert.NoError(t, c.Check(value.NewString("abc")))
+
+	err := c.Check(value.NewString("aBc"))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "lowercase")
+	assert.True(t, errors.Is(err, errNotLowercase))
+}
+
+func TestFuncApplicableTo(t *testing.T) {
+	c := newLowercaseFunc()
+
+	assert.True(t, constraint.ApplicableTo(c, value.NewString("")))
+	assert.True(t, constraint.ApplicableTo(c, value.NewStringSlice()))
+	assert.False(t, constraint.ApplicableTo(c, value.NewInt(0)))
+
+	anyType := constraint.NewFunc("any", "", func(v value.Value) error { return nil })
+
+	assert.True(t, constraint.ApplicableTo(anyType, value.NewInt(0)))
+}
+
+var errNotLowercase = errors.New("not lowercase")
+
+func newLowercaseFunc() *constraint.Func {
+	check := func(v value.Value) error {
+		str := v.(value.Single).Value().(string)
+		if strings.ToLower(str) != str {
+			return errNotLowercase
+		}
+
+		return nil
+	}
+
+	return constraint.NewFunc("lowercase", "must be all lowercase", check, value.TypeString)
+}
+
+func TestFuncNilCheck(t *testing.T) {
+	assert.Panics(t, func() { constraint.NewFunc("even", "x is even", nil) })
+	assert.Panics(t, func() {
+		constraint.NewFuncWithCompatibility("even", "x is even", nil,
+			func(constraint.Constraint) (bool, error) { return true, nil })
+	})
+}
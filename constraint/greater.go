@@ -30,3 +30,12 @@ func (c *Greater) CompatibleWith(c2 Constraint) (bool, error) {
 
 	return true, nil
 }
+
+// Check returns a non-nil error if the value (or any slice value) is
+// not greater than the parameter.
+func (c *Greater) Check(v value.Value) error {
+	return checkCompare(c, v, v.Greater)
+}
+
+// String returns a readable description of the constraint.
+func (c *Greater) String() string { return "x > " + FormatValue(c.val) }
@@ -38,3 +38,15 @@ func TestGreater(t *testing.T) {
 		assert.False(t, result)
 	}
 }
+
+func TestGreaterCheck(t *testing.T) {
+	c := constraint.NewGreater(value.NewFloat(2.5))
+
+	assert.Equal(t, "x > 2.5", c.String())
+	assert.NoError(t, c.Check(value.NewFloat(3.0)))
+	assert.NoError(t, c.Check(value.NewFloatSlice(3.0, 4.0)))
+	assert.NoError(t, c.Check(value.NewFloatSlice()))
+	assert.Error(t, c.Check(value.NewFloat(2.5)))
+	assert.Error(t, c.Check(value.NewFloatSlice(3.0, 2.0)))
+	assert.Error(t, c.Check(value.NewInt(3)))
+}
@@ -32,3 +32,12 @@ func (c *GreaterEqual) CompatibleWith(c2 Constraint) (bool, error) {
 
 	return true, nil
 }
+
+// Check returns a non-nil error if the value (or any slice value) is
+// not greater than or equal to the parameter.
+func (c *GreaterEqual) Check(v value.Value) error {
+	return checkCompare(c, v, v.GreaterEqual)
+}
+
+// String returns a readable description of the constraint.
+func (c *GreaterEqual) String() string { return "x >= " + FormatValue(c.val) }
@@ -0,0 +1,173 @@
+package constraint
+
+import (
+	"errors"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// Implies returns true if every value that satisfies c1 also satisfies c2,
+// so that c1 is at least as strict as c2. This is known for bounds in the
+// same direction, OneOf and NoneOf sets, lengths, multiples, sums, composites
+// of these, and identical constraints of other types. A OneOf implies any
+// constraint that all of its values satisfy.
+// Returns false if the implication is not known to hold.
+// Returns a non-nil error in case of failure.
+func Implies(c1, c2 Constraint) (bool, error) {
+	if c1.Type() == c2.Type() && c1.String() == c2.String() {
+		if c1.Type() == TypeFunc {
+			return c1.(*Func).Name() == c2.(*Func).Name(), nil
+		}
+
+		return true, nil
+	}
+
+	switch c1.Type() {
+	case TypeAnyOf:
+		return impliesAll(c1.(Composite).Constraints(), []Constraint{c2})
+	case TypeNot:
+		if c2.Type() == TypeNot {
+			return Implies(c2.(Composite).Constraints()[0], c1.(Composite).Constraints()[0])
+		}
+	case TypeEach:
+		if c2.Type() == TypeEach {
+			return impliesAll(
+				[]Constraint{NewAllOf(c1.(Composite).Constraints()...)},
+				c2.(Composite).Constraints())
+		}
+	}
+
+	switch c2.Type() {
+	case TypeAllOf:
+		return impliesAll([]Constraint{c1}, c2.(Composite).Constraints())
+	case TypeAnyOf:
+		return impliesAny(c1, c2.(Composite).Constraints())
+	}
+
+	if c1.Type() == TypeAllOf {
+		return anyImplies(c1.(Composite).Constraints(), c2)
+	}
+
+	return impliesSimple(c1, c2)
+}
+
+// impliesAll returns true if each of cs1 implies each of cs2.
+func impliesAll(cs1, cs2 []Constraint) (bool, error) {
+	for _, c1 := range cs1 {
+		for _, c2 := range cs2 {
+			implied, err := Implies(c1, c2)
+			if err != nil || !implied {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// impliesAny returns true if c1 implies any of cs2.
+func impliesAny(c1 Constraint, cs2 []Constraint) (bool, error) {
+	for _, c2 := range cs2 {
+		implied, err := Implies(c1, c2)
+		if err != nil || implied {
+			return implied, err
+		}
+	}
+
+	return false, nil
+}
+
+// anyImplies returns true if any of cs1 implies c2.
+func anyImplies(cs1 []Constraint, c2 Constraint) (bool, error) {
+	for _, c1 := range cs1 {
+		implied, err := Implies(c1, c2)
+		if err != nil || implied {
+			return implied, err
+		}
+	}
+
+	return false, nil
+}
+
+// impliesSimple compares constraints that are not composites.
+func impliesSimple(c1, c2 Constraint) (bool, error) {
+	switch c1.Type() {
+	case TypeOneOf:
+		vals := c1.Param().(value.Slice)
+
+		for i := 0; i < vals.Len(); i++ {
+			ok, err := satisfies(c2, vals.Index(i))
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+
+		return true, nil
+	case TypeGreater, TypeGreaterEqual:
+		if c2.Type() == TypeGreater || c2.Type() == TypeGreaterEqual {
+			return boundImplies(c1, c2, TypeGreaterEqual, TypeGreater)
+		}
+	case TypeLess, TypeLessEqual:
+		if c2.Type() == TypeLess || c2.Type() == TypeLessEqual {
+			return boundImplies(c1, c2, TypeLessEqual, TypeLess)
+		}
+	case TypeNoneOf:
+		if c2.Type() == TypeNoneOf {
+			return coversAll(c1.Param().(value.Slice), c2.Param().(value.Slice))
+		}
+	case TypeMinLen:
+		if c2.Type() == TypeMinLen {
+			return c1.Param().GreaterEqual(c2.Param().(value.Single))
+		}
+	case TypeMaxLen, TypeSumLessEqual:
+		if c2.Type() == c1.Type() {
+			return c1.Param().LessEqual(c2.Param().(value.Single))
+		}
+	case TypeMultipleOf:
+		if c2.Type() == TypeMultipleOf {
+			// multiples of c1 are multiples of c2 if the c1 step is a
+			// multiple of the c2 step
+			return satisfies(c2, c1.Param())
+		}
+	}
+
+	return false, nil
+}
+
+// boundImplies compares lower bounds (or upper bounds) c1 and c2, where the
+// inclusive and exclusive types are given.
+func boundImplies(c1, c2 Constraint, inclusive, exclusive Type) (bool, error) {
+	v1 := c1.Param()
+	v2 := c2.Param().(value.Single)
+
+	equal, err := v1.(value.Single).Equal(v2)
+	if err != nil {
+		return false, err
+	}
+
+	if equal {
+		return c1.Type() == exclusive || c2.Type() == inclusive, nil
+	}
+
+	if inclusive == TypeGreaterEqual {
+		return v1.Greater(v2)
+	}
+
+	return v1.Less(v2)
+}
+
+// satisfies returns true if the value satisfies the constraint, and false if it
+// is violated or not applicable.
+// Returns a non-nil error in case of other failure.
+func satisfies(c Constraint, v value.Value) (bool, error) {
+	err := c.Check(v)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, ErrViolation) || errors.Is(err, ErrNotApplicable) {
+		return false, nil
+	}
+
+	return false, err
+}
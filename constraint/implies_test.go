@@ -0,0 +1,97 @@
+package constraint_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImplies(t *testing.T) {
+	even := constraint.NewFunc("even", "x is even", func(v value.Value) error { return nil })
+	odd := constraint.NewFunc("odd", "x is even", func(v value.Value) error { return nil })
+
+	testCases := []struct {
+		c1, c2   constraint.Constraint
+		expected bool
+	}{
+		{constraint.NewLessEqual(value.NewInt(5)), constraint.NewLessEqual(value.NewInt(10)), true},
+		{constraint.NewLessEqual(value.NewInt(10)), constraint.NewLessEqual(value.NewInt(5)), false},
+		{constraint.NewLess(value.NewInt(10)), constraint.NewLessEqual(value.NewInt(10)), true},
+		{constraint.NewLessEqual(value.NewInt(10)), constraint.NewLess(value.NewInt(10)), false},
+		{constraint.NewGreater(value.NewInt(0)), constraint.NewGreaterEqual(value.NewInt(0)), true},
+		{constraint.NewGreaterEqual(value.NewInt(0)), constraint.NewGreater(value.NewInt(0)), false},
+		{constraint.NewGreaterEqual(value.NewInt(1)), constraint.NewGreater(value.NewInt(0)), true},
+		{constraint.NewGreater(value.NewInt(0)), constraint.NewLess(value.NewInt(10)), false},
+		{constraint.NewOneOf(value.NewStringSlice("a")), constraint.NewOneOf(value.NewStringSlice("a", "b")), true},
+		{constraint.NewOneOf(value.NewStringSlice("a", "b")), constraint.NewOneOf(value.NewStringSlice("a")), false},
+		{constraint.NewOneOf(value.NewIntSlice(2, 4)), constraint.NewLessEqual(value.NewInt(4)), true},
+		{constraint.NewOneOf(value.NewStringSlice("ab")), constraint.NewMaxLen(1), false},
+		{constraint.NewNoneOf(value.NewStringSlice("a", "b")), constraint.NewNoneOf(value.NewStringSlice("a")), true},
+		{constraint.NewNoneOf(value.NewStringSlice("a")), constraint.NewNoneOf(value.NewStringSlice("a", "b")), false},
+		{constraint.NewMinLen(2), constraint.NewMinLen(1), true},
+		{constraint.NewMaxLen(2), constraint.NewMaxLen(1), false},
+		{constraint.NewSumLessEqual(value.NewInt(5)), constraint.NewSumLessEqual(value.NewInt(5)), true},
+		{constraint.NewMultipleOf(value.NewInt(4)), constraint.NewMultipleOf(value.NewInt(2)), true},
+		{constraint.NewMultipleOf(value.NewInt(2)), constraint.NewMultipleOf(value.NewInt(4)), false},
+		{constraint.NewUnique(), constraint.NewUnique(), true},
+		{constraint.NewSortedAscending(), constraint.NewSortedDescending(), false},
+		{constraint.NewPattern(regexp.MustCompile("^a")), constraint.NewPattern(regexp.MustCompile("^a")), true},
+		{even, even, true},
+		{even, odd, false},
+		{
+			constraint.NewEach(constraint.NewLessEqual(value.NewInt(5)), constraint.NewMinLen(0)),
+			constraint.NewEach(constraint.NewLessEqual(value.NewInt(10))),
+			true,
+		},
+		{
+			constraint.NewEach(constraint.NewLessEqual(value.NewInt(10))),
+			constraint.NewEach(constraint.NewLessEqual(value.NewInt(5))),
+			false,
+		},
+		{
+			constraint.NewAllOf(constraint.NewGreater(value.NewInt(0)), constraint.NewLess(value.NewInt(5))),
+			constraint.NewLess(value.NewInt(10)),
+			true,
+		},
+		{
+			constraint.NewLess(value.NewInt(5)),
+			constraint.NewAllOf(constraint.NewGreater(value.NewInt(0)), constraint.NewLess(value.NewInt(10))),
+			false,
+		},
+		{
+			constraint.NewLess(value.NewInt(5)),
+			constraint.NewAnyOf(constraint.NewGreater(value.NewInt(100)), constraint.NewLess(value.NewInt(10))),
+			true,
+		},
+		{
+			constraint.NewAnyOf(constraint.NewLess(value.NewInt(0)), constraint.NewLess(value.NewInt(20))),
+			constraint.NewLess(value.NewInt(10)),
+			false,
+		},
+		{
+			constraint.NewNot(constraint.NewOneOf(value.NewStringSlice("root", "admin"))),
+			constraint.NewNot(constraint.NewOneOf(value.NewStringSlice("root"))),
+			true,
+		},
+		{
+			constraint.NewNot(constraint.NewOneOf(value.NewStringSlice("root"))),
+			constraint.NewNot(constraint.NewOneOf(value.NewStringSlice("root", "admin"))),
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		implied, err := constraint.Implies(tc.c1, tc.c2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, implied, "%s implies %s", tc.c1, tc.c2)
+	}
+
+	_, err := constraint.Implies(
+		constraint.NewLessEqual(value.NewInt(5)), constraint.NewLessEqual(value.NewString("x")))
+
+	assert.Error(t, err)
+}
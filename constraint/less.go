@@ -30,3 +30,12 @@ func (c *Less) CompatibleWith(c2 Constraint) (bool, error) {
 
 	return true, nil
 }
+
+// Check returns a non-nil error if the value (or any slice value) is
+// not less than the parameter.
+func (c *Less) Check(v value.Value) error {
+	return checkCompare(c, v, v.Less)
+}
+
+// String returns a readable description of the constraint.
+func (c *Less) String() string { return "x < " + FormatValue(c.val) }
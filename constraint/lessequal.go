@@ -32,3 +32,12 @@ func (c *LessEqual) CompatibleWith(c2 Constraint) (bool, error) {
 
 	return true, nil
 }
+
+// Check returns a non-nil error if the value (or any slice value) is
+// not less than or equal to the parameter.
+func (c *LessEqual) Check(v value.Value) error {
+	return checkCompare(c, v, v.LessEqual)
+}
+
+// String returns a readable description of the constraint.
+func (c *LessEqual) String() string { return "x <= " + FormatValue(c.val) }
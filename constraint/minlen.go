@@ -30,3 +30,11 @@ func (c *MinLen) CompatibleWith(c2 Constraint) (bool, error) {
 
 	return true, nil
 }
+
+// Check returns a non-nil error if the slice or string value is too short.
+func (c *MinLen) Check(v value.Value) error {
+	return checkLen(c, v, func(a, b uint64) bool { return a >= b })
+}
+
+// String returns a readable description of the constraint.
+func (c *MinLen) String() string { return "len(x) >= " + FormatValue(c.val) }
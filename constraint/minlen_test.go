@@ -39,3 +39,14 @@ func TestMinLen(t *testing.T) {
 		assert.False(t, result)
 	}
 }
+
+func TestMinLenCheck(t *testing.T) {
+	c := constraint.NewMinLen(2)
+
+	assert.Equal(t, "len(x) >= 2", c.String())
+	assert.NoError(t, c.Check(value.NewString("ab")))
+	assert.NoError(t, c.Check(value.NewIntSlice(1, 2, 3)))
+	assert.Error(t, c.Check(value.NewString("a")))
+	assert.Error(t, c.Check(value.NewIntSlice()))
+	assert.Error(t, c.Check(value.NewInt(7)))
+}
@@ -0,0 +1,243 @@
+package constraint
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// DefaultTolerance is the relative tolerance used by MultipleOf for float values.
+const DefaultTolerance = 1e-9
+
+// MultipleOf restricts a numeric value (or each slice value) to be a whole
+// multiple of the parameter. Float values are compared using a tolerance
+// relative to the parameter.
+type MultipleOf struct {
+	val       value.Single
+	tolerance float64
+}
+
+// NewMultipleOf makes a new MultipleOf constraint, with the default tolerance
+func NewMultipleOf(val value.Single) *MultipleOf {
+	return &MultipleOf{val: val, tolerance: DefaultTolerance}
+}
+
+// NewMultipleOfWithTolerance makes a new MultipleOf constraint with the given tolerance
+func NewMultipleOfWithTolerance(val value.Single, tolerance float64) *MultipleOf {
+	return &MultipleOf{val: val, tolerance: tolerance}
+}
+
+// Type returns the constraint type.
+func (c *MultipleOf) Type() Type { return TypeMultipleOf }
+
+// Param returns the constraint parameter.
+func (c *MultipleOf) Param() value.Value { return c.val }
+
+// Tolerance returns the relative tolerance used for float values.
+func (c *MultipleOf) Tolerance() float64 { return c.tolerance }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// A OneOf constraint is incompatible if none of its values is a multiple.
+// Bounds are checked together by CompatibleSet.
+// Returns a non-nil error in case of failure.
+func (c *MultipleOf) CompatibleWith(c2 Constraint) (bool, error) {
+	switch c2.Type() {
+	case TypeMultipleOf:
+		return false, nil
+	case TypeOneOf:
+		vals := c2.Param().(value.Slice)
+		if err := value.CheckType(c.val.Type(), vals.Type()); err != nil {
+			return false, err
+		}
+
+		for i := 0; i < vals.Len(); i++ {
+			if c.Check(vals.Index(i)) == nil {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error if the value (or any slice value) is not a multiple.
+func (c *MultipleOf) Check(v value.Value) error {
+	switch vv := v.(type) {
+	case value.Single:
+		return c.checkSingle(vv)
+	case value.Slice:
+		for i := 0; i < vv.Len(); i++ {
+			if err := c.checkSingle(vv.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *MultipleOf) String() string { return "x % " + FormatValue(c.val) + " == 0" }
+
+func (c *MultipleOf) checkSingle(v value.Single) error {
+	if err := value.CheckType(c.val.Type(), v.Type()); err != nil {
+		return err
+	}
+
+	var ok bool
+
+	switch step := c.val.Value().(type) {
+	case int64:
+		if step == 0 {
+			return errZeroStep
+		}
+
+		ok = v.Value().(int64)%step == 0
+	case uint64:
+		if step == 0 {
+			return errZeroStep
+		}
+
+		ok = v.Value().(uint64)%step == 0
+	case float64:
+		if step == 0.0 {
+			return errZeroStep
+		}
+
+		x := v.Value().(float64)
+		ok = math.Abs(x-math.Round(x/step)*step) <= c.tolerance*math.Abs(step)
+	default:
+		return notApplicable(c, v)
+	}
+
+	if !ok {
+		return violation(c, v)
+	}
+
+	return nil
+}
+
+// hasMultipleWithin returns true if there is at least one multiple that
+// satisfies both bounds. A nil bound is unbounded.
+func (c *MultipleOf) hasMultipleWithin(lower, upper Constraint) (bool, error) {
+	if lower == nil || upper == nil {
+		return true, nil
+	}
+
+	lo := lower.Param().(value.Single)
+	hi := upper.Param().(value.Single)
+
+	for _, bound := range []value.Single{lo, hi} {
+		if err := value.CheckType(c.val.Type(), bound.Type()); err != nil {
+			return false, err
+		}
+	}
+
+	loExcl := lower.Type() == TypeGreater
+	hiExcl := upper.Type() == TypeLess
+
+	switch step := c.val.Value().(type) {
+	case int64:
+		return intMultipleWithin(step, lo.Value().(int64), hi.Value().(int64), loExcl, hiExcl), nil
+	case uint64:
+		return uintMultipleWithin(step, lo.Value().(uint64), hi.Value().(uint64), loExcl, hiExcl), nil
+	case float64:
+		return c.floatMultipleWithin(step, lo.Value().(float64), hi.Value().(float64), loExcl, hiExcl), nil
+	}
+
+	return true, nil
+}
+
+func intMultipleWithin(step, lo, hi int64, loExcl, hiExcl bool) bool {
+	if loExcl {
+		if lo == math.MaxInt64 {
+			return false
+		}
+
+		lo++
+	}
+
+	if hiExcl {
+		if hi == math.MinInt64 {
+			return false
+		}
+
+		hi--
+	}
+
+	if step == 0 || lo > hi {
+		return false
+	}
+
+	// Work with unsigned distances from lo so that neither the step
+	// magnitude nor the next multiple can overflow.
+	mag := uint64(step)
+	if step < 0 {
+		mag = -mag
+	}
+
+	var dist uint64
+
+	switch r := lo % step; {
+	case r < 0:
+		dist = uint64(-r)
+	case r > 0:
+		dist = mag - uint64(r)
+	}
+
+	return dist <= uint64(hi)-uint64(lo)
+}
+
+func uintMultipleWithin(step, lo, hi uint64, loExcl, hiExcl bool) bool {
+	if loExcl {
+		if lo == math.MaxUint64 {
+			return false
+		}
+
+		lo++
+	}
+
+	if hiExcl {
+		if hi == 0 {
+			return false
+		}
+
+		hi--
+	}
+
+	if step == 0 || lo > hi {
+		return false
+	}
+
+	var dist uint64
+	if r := lo % step; r > 0 {
+		dist = step - r
+	}
+
+	return dist <= hi-lo
+}
+
+func (c *MultipleOf) floatMultipleWithin(step, lo, hi float64, loExcl, hiExcl bool) bool {
+	step = math.Abs(step)
+	if step == 0.0 {
+		return false
+	}
+
+	tol := c.tolerance * step
+	first := math.Ceil((lo-tol)/step) * step
+
+	if loExcl && first <= lo+tol {
+		first += step
+	}
+
+	if hiExcl {
+		return first < hi-tol
+	}
+
+	return first <= hi+tol
+}
+
+var errZeroStep = errors.New("multipleOf parameter must be non-zero")
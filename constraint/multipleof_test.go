@@ -0,0 +1,160 @@
+package constraint_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipleOf(t *testing.T) {
+	c := constraint.NewMultipleOf(value.NewInt(4096))
+
+	assert.Equal(t, c.Type(), constraint.TypeMultipleOf)
+	assert.Equal(t, constraint.DefaultTolerance, c.Tolerance())
+	assert.Equal(t, "x % 4096 == 0", c.String())
+
+	compatible := []constraint.Constraint{
+		constraint.NewGreater(value.NewInt(0)),
+		constraint.NewLess(value.NewInt(10)),
+		constraint.NewOneOf(value.NewIntSlice(100, 8192)),
+		constraint.NewUnique(),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewMultipleOf(value.NewInt(2)),
+		constraint.NewOneOf(value.NewIntSlice(100, 200)),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+}
+
+func TestMultipleOfCheck(t *testing.T) {
+	even := constraint.NewMultipleOf(value.NewUInt(2))
+	half := constraint.NewMultipleOf(value.NewFloat(0.5))
+
+	assert.NoError(t, even.Check(value.NewUInt(4)))
+	assert.NoError(t, even.Check(value.NewUIntSlice(0, 2, 8)))
+	assert.Error(t, even.Check(value.NewUInt(3)))
+	assert.Error(t, even.Check(value.NewUIntSlice(2, 3)))
+	assert.Error(t, even.Check(value.NewInt(4)))
+
+	assert.NoError(t, half.Check(value.NewFloat(2.5)))
+	assert.NoError(t, half.Check(value.NewFloat(0.1+0.2+0.2)))
+	assert.Error(t, half.Check(value.NewFloat(2.25)))
+
+	assert.NoError(t, constraint.NewMultipleOf(value.NewInt(-3)).Check(value.NewInt(9)))
+	assert.Error(t, constraint.NewMultipleOf(value.NewInt(0)).Check(value.NewInt(0)))
+
+	loose := constraint.NewMultipleOfWithTolerance(value.NewFloat(0.5), 0.1)
+
+	assert.NoError(t, loose.Check(value.NewFloat(2.52)))
+}
+
+func TestCompatibleSetWithMultipleOf(t *testing.T) {
+	testCases := []struct {
+		constraints []constraint.Constraint
+		expected    bool
+	}{
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(4096)),
+			constraint.NewGreater(value.NewInt(0)),
+			constraint.NewLess(value.NewInt(4096)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(4096)),
+			constraint.NewGreater(value.NewInt(0)),
+			constraint.NewLessEqual(value.NewInt(4096)),
+		}, true},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(10)),
+			constraint.NewGreaterEqual(value.NewInt(-19)),
+			constraint.NewLess(value.NewInt(-10)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(10)),
+			constraint.NewGreaterEqual(value.NewInt(-20)),
+			constraint.NewLess(value.NewInt(-10)),
+		}, true},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewUInt(2)),
+			constraint.NewGreater(value.NewUInt(2)),
+			constraint.NewLess(value.NewUInt(4)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewFloat(0.5)),
+			constraint.NewGreater(value.NewFloat(1.0)),
+			constraint.NewLess(value.NewFloat(1.5)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewFloat(0.5)),
+			constraint.NewGreater(value.NewFloat(1.0)),
+			constraint.NewLessEqual(value.NewFloat(1.5)),
+		}, true},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(7)),
+			constraint.NewGreater(value.NewInt(1)),
+		}, true},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(10)),
+			constraint.NewGreaterEqual(value.NewInt(math.MaxInt64 - 5)),
+			constraint.NewLessEqual(value.NewInt(math.MaxInt64)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(10)),
+			constraint.NewGreaterEqual(value.NewInt(math.MinInt64)),
+			constraint.NewLessEqual(value.NewInt(math.MinInt64 + 5)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(math.MinInt64)),
+			constraint.NewGreaterEqual(value.NewInt(math.MinInt64)),
+			constraint.NewLess(value.NewInt(0)),
+		}, true},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(1)),
+			constraint.NewGreater(value.NewInt(math.MaxInt64)),
+			constraint.NewLessEqual(value.NewInt(math.MaxInt64)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewInt(1)),
+			constraint.NewGreaterEqual(value.NewInt(math.MinInt64)),
+			constraint.NewLess(value.NewInt(math.MinInt64)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewUInt(1)),
+			constraint.NewGreater(value.NewUInt(math.MaxUint64)),
+			constraint.NewLessEqual(value.NewUInt(math.MaxUint64)),
+		}, false},
+		{[]constraint.Constraint{
+			constraint.NewMultipleOf(value.NewUInt(10)),
+			constraint.NewGreater(value.NewUInt(math.MaxUint64 - 5)),
+			constraint.NewLessEqual(value.NewUInt(math.MaxUint64)),
+		}, false},
+	}
+
+	for _, tc := range testCases {
+		result, err := constraint.CompatibleSet(tc.constraints)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, result, "constraints %v", tc.constraints)
+	}
+
+	_, err := constraint.CompatibleSet([]constraint.Constraint{
+		constraint.NewMultipleOf(value.NewInt(2)),
+		constraint.NewGreater(value.NewFloat(1.0)),
+		constraint.NewLess(value.NewFloat(5.0)),
+	})
+
+	assert.Error(t, err)
+}
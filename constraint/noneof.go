@@ -0,0 +1,91 @@
+package constraint
+
+import (
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// NoneOf restricts a value (or each slice value) to not be any of those
+// in the slice parameter
+type NoneOf struct {
+	val value.Slice
+}
+
+// NewNoneOf makes a new NoneOf constraint
+func NewNoneOf(val value.Slice) *NoneOf {
+	return &NoneOf{val: val}
+}
+
+// Type returns the constraint type.
+func (c *NoneOf) Type() Type { return TypeNoneOf }
+
+// Param returns the constraint parameter.
+func (c *NoneOf) Param() value.Value { return c.val }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// A OneOf constraint is incompatible if all of its values are forbidden.
+// Returns a non-nil error in case of failure.
+func (c *NoneOf) CompatibleWith(c2 Constraint) (bool, error) {
+	switch c2.Type() {
+	case TypeNoneOf:
+		return false, nil
+	case TypeOneOf:
+		covered, err := coversAll(c.val, c2.Param().(value.Slice))
+		if err != nil {
+			return false, err
+		}
+
+		return !covered, nil
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error for the first forbidden value found. For a slice,
+// the error value is the forbidden element.
+func (c *NoneOf) Check(v value.Value) error {
+	switch vv := v.(type) {
+	case value.Single:
+		return c.checkSingle(vv)
+	case value.Slice:
+		for i := 0; i < vv.Len(); i++ {
+			if err := c.checkSingle(vv.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *NoneOf) String() string { return "x not in " + FormatValue(c.val) }
+
+func (c *NoneOf) checkSingle(v value.Single) error {
+	forbidden, err := c.val.Contains(v)
+	if err != nil {
+		return err
+	}
+
+	if forbidden {
+		return violation(c, v)
+	}
+
+	return nil
+}
+
+// coversAll returns true if every value in the second slice is also
+// contained in the first.
+func coversAll(s1, s2 value.Slice) (bool, error) {
+	if err := value.CheckType(s1.Type(), s2.Type()); err != nil {
+		return false, err
+	}
+
+	for i := 0; i < s2.Len(); i++ {
+		found, err := s1.Contains(s2.Index(i))
+		if err != nil || !found {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoneOf(t *testing.T) {
+	c := constraint.NewNoneOf(value.NewUIntSlice(22, 25))
+	p := c.Param()
+
+	assert.Equal(t, c.Type(), constraint.TypeNoneOf)
+	assert.Equal(t, p.Type(), value.TypeUInt)
+	assert.True(t, p.IsSlice())
+	assert.Equal(t, "x not in [22, 25]", c.String())
+
+	compatible := []constraint.Constraint{
+		constraint.NewMaxLen(0),
+		constraint.NewMinLen(0),
+		constraint.NewGreater(value.NewUInt(0)),
+		constraint.NewGreaterEqual(value.NewUInt(0)),
+		constraint.NewLess(value.NewUInt(0)),
+		constraint.NewLessEqual(value.NewUInt(0)),
+		constraint.NewOneOf(value.NewUIntSlice(22, 80)),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewNoneOf(value.NewUIntSlice(80)),
+		constraint.NewOneOf(value.NewUIntSlice(22)),
+		constraint.NewOneOf(value.NewUIntSlice(25, 22)),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+
+	_, err := c.CompatibleWith(constraint.NewOneOf(value.NewIntSlice(22)))
+
+	assert.Error(t, err)
+}
+
+func TestNoneOfCheck(t *testing.T) {
+	c := constraint.NewNoneOf(value.NewStringSlice("root", "admin"))
+
+	assert.NoError(t, c.Check(value.NewString("bob")))
+	assert.NoError(t, c.Check(value.NewStringSlice("bob", "alice")))
+	assert.NoError(t, c.Check(value.NewStringSlice()))
+
+	err := c.Check(value.NewStringSlice("bob", "admin"))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "admin")
+
+	err = c.Check(value.NewString("root"))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "root")
+	assert.Error(t, c.Check(value.NewInt(1)))
+}
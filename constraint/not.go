@@ -0,0 +1,55 @@
+package constraint
+
+import (
+	"errors"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// Not restricts a value to not satisfy the wrapped constraint
+type Not struct {
+	constraint Constraint
+}
+
+// NewNot makes a new Not constraint
+func NewNot(c Constraint) *Not {
+	return &Not{constraint: c}
+}
+
+// Type returns the constraint type.
+func (c *Not) Type() Type { return TypeNot }
+
+// Param returns nil, since the wrapped constraint has its own parameter.
+func (c *Not) Param() value.Value { return nil }
+
+// Constraints returns the wrapped constraint.
+func (c *Not) Constraints() []Constraint { return []Constraint{c.constraint} }
+
+// CompatibleWith returns true unless the given constraint is the same type
+// of constraint with an identical description, which could never be satisfied.
+// Returns a non-nil error in case of failure.
+func (c *Not) CompatibleWith(c2 Constraint) (bool, error) {
+	if c2.Type() == c.constraint.Type() && c2.String() == c.constraint.String() {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error if the wrapped constraint is satisfied, or if
+// it fails for a reason other than a violation (such as a type mismatch).
+func (c *Not) Check(v value.Value) error {
+	err := c.constraint.Check(v)
+	if err == nil {
+		return violation(c, v)
+	}
+
+	if errors.Is(err, ErrViolation) {
+		return nil
+	}
+
+	return err
+}
+
+// String returns a readable description of the constraint.
+func (c *Not) String() string { return "!(" + c.constraint.String() + ")" }
@@ -0,0 +1,53 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNot(t *testing.T) {
+	oneOf := constraint.NewOneOf(value.NewStringSlice("root", "admin"))
+	c := constraint.NewNot(oneOf)
+
+	assert.Equal(t, c.Type(), constraint.TypeNot)
+	assert.Nil(t, c.Param())
+	assert.Equal(t, []constraint.Constraint{oneOf}, c.Constraints())
+	assert.Equal(t, "!(x in [root, admin])", c.String())
+
+	compatible := []constraint.Constraint{
+		constraint.NewMaxLen(10),
+		constraint.NewOneOf(value.NewStringSlice("root", "bob")),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewOneOf(value.NewStringSlice("root", "admin")),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+}
+
+func TestNotCheck(t *testing.T) {
+	c := constraint.NewNot(constraint.NewOneOf(value.NewStringSlice("root", "admin")))
+
+	assert.NoError(t, c.Check(value.NewString("bob")))
+	assert.Error(t, c.Check(value.NewString("root")))
+}
+
+func TestApplicableToWithComposite(t *testing.T) {
+	c := constraint.NewNot(constraint.NewOneOf(value.NewIntSlice(1)))
+
+	assert.True(t, constraint.ApplicableTo(c, value.NewInt(0)))
+	assert.False(t, constraint.ApplicableTo(c, value.NewIntSlice()))
+}
@@ -24,7 +24,36 @@ func (c *OneOf) CompatibleWith(c2 Constraint) (bool, error) {
 	switch c2.Type() {
 	case TypeOneOf, TypeGreater, TypeGreaterEqual, TypeLess, TypeLessEqual:
 		return false, nil
+	case TypeNoneOf:
+		covered, err := coversAll(c2.Param().(value.Slice), c.val)
+		if err != nil {
+			return false, err
+		}
+
+		return !covered, nil
 	}
 
 	return true, nil
 }
+
+// Check returns a non-nil error if the value is not one of the parameter values.
+func (c *OneOf) Check(v value.Value) error {
+	single, ok := v.(value.Single)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	ok, err := single.OneOf(c.val)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return violation(c, v)
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *OneOf) String() string { return "x in " + FormatValue(c.val) }
@@ -40,3 +40,12 @@ func TestOneOf(t *testing.T) {
 		assert.False(t, result)
 	}
 }
+
+func TestOneOfCheck(t *testing.T) {
+	c := constraint.NewOneOf(value.NewStringSlice("debug", "info"))
+
+	assert.Equal(t, "x in [debug, info]", c.String())
+	assert.NoError(t, c.Check(value.NewString("info")))
+	assert.Error(t, c.Check(value.NewString("warn")))
+	assert.Error(t, c.Check(value.NewStringSlice("info")))
+}
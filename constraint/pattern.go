@@ -0,0 +1,75 @@
+package constraint
+
+import (
+	"regexp"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// Pattern restricts a string value to match a regular expression. Use Each
+// to restrict each value of a string slice.
+type Pattern struct {
+	re *regexp.Regexp
+}
+
+// NewPattern makes a new Pattern constraint
+func NewPattern(re *regexp.Regexp) *Pattern {
+	return &Pattern{re: re}
+}
+
+// Type returns the constraint type.
+func (c *Pattern) Type() Type { return TypePattern }
+
+// Param returns the constraint parameter, which is the regular expression source.
+func (c *Pattern) Param() value.Value { return value.NewString(c.re.String()) }
+
+// Regexp returns the regular expression.
+func (c *Pattern) Regexp() *regexp.Regexp { return c.re }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// A OneOf constraint is incompatible if none of its values match.
+// Returns a non-nil error in case of failure.
+func (c *Pattern) CompatibleWith(c2 Constraint) (bool, error) {
+	if c2.Type() != TypeOneOf {
+		return true, nil
+	}
+
+	vals := c2.Param().(value.Slice)
+	if err := value.CheckType(value.TypeString, vals.Type()); err != nil {
+		return false, err
+	}
+
+	for i := 0; i < vals.Len(); i++ {
+		if c.Check(vals.Index(i)) == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Check returns a non-nil error if the value does not match.
+func (c *Pattern) Check(v value.Value) error {
+	single, ok := v.(value.Single)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	return c.checkSingle(single)
+}
+
+// String returns a readable description of the constraint.
+func (c *Pattern) String() string { return "x matches " + c.re.String() }
+
+func (c *Pattern) checkSingle(v value.Single) error {
+	str, ok := v.Value().(string)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	if !c.re.MatchString(str) {
+		return violation(c, v)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package constraint_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPattern(t *testing.T) {
+	c := constraint.NewPattern(regexp.MustCompile(`^[a-z]+$`))
+
+	assert.Equal(t, c.Type(), constraint.TypePattern)
+	assert.Equal(t, "x matches ^[a-z]+$", c.String())
+	assert.Equal(t, value.NewString("^[a-z]+$"), c.Param())
+
+	compatible := []constraint.Constraint{
+		constraint.NewMinLen(1),
+		constraint.NewOneOf(value.NewStringSlice("abc", "ABC")),
+		constraint.NewNoneOf(value.NewStringSlice("admin")),
+	}
+	incompatible := []constraint.Constraint{
+		constraint.NewOneOf(value.NewStringSlice("ABC", "1")),
+	}
+
+	for _, c2 := range compatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.True(t, result)
+	}
+
+	for _, c2 := range incompatible {
+		result, err := constraint.Compatible(c, c2)
+		assert.NoError(t, err)
+		assert.False(t, result)
+	}
+
+	_, err := constraint.Compatible(c, constraint.NewOneOf(value.NewIntSlice(1)))
+	assert.Error(t, err)
+}
+
+func TestPatternCheck(t *testing.T) {
+	c := constraint.NewPattern(regexp.MustCompile(`^[a-z]+$`))
+
+	assert.NoError(t, c.Check(value.NewString("acme")))
+	assert.Error(t, c.Check(value.NewString("Acme")))
+	assert.Error(t, c.Check(value.NewStringSlice("acme")))
+	assert.Error(t, c.Check(value.NewInt(1)))
+}
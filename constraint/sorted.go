@@ -0,0 +1,89 @@
+package constraint
+
+import (
+	"fmt"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+const (
+	// SortAscending is the Sorted parameter for ascending order
+	SortAscending = "ascending"
+	// SortDescending is the Sorted parameter for descending order
+	SortDescending = "descending"
+)
+
+// Sorted restricts a slice value to be in ascending or descending order.
+// Equal neighbouring elements are allowed.
+type Sorted struct {
+	val value.Single
+}
+
+// NewSortedAscending makes a new Sorted constraint for ascending order
+func NewSortedAscending() *Sorted {
+	return &Sorted{val: value.NewString(SortAscending)}
+}
+
+// NewSortedDescending makes a new Sorted constraint for descending order
+func NewSortedDescending() *Sorted {
+	return &Sorted{val: value.NewString(SortDescending)}
+}
+
+// Type returns the constraint type.
+func (c *Sorted) Type() Type { return TypeSorted }
+
+// Param returns the constraint parameter, which is the sort order.
+func (c *Sorted) Param() value.Value { return c.val }
+
+// Descending returns true if the order is descending.
+func (c *Sorted) Descending() bool { return c.val.Value() == SortDescending }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// Returns a non-nil error in case of failure.
+func (c *Sorted) CompatibleWith(c2 Constraint) (bool, error) {
+	if c2.Type() == TypeSorted {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error naming the first slice element that is out of order.
+func (c *Sorted) Check(v value.Value) error {
+	slice, ok := v.(value.Slice)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	for i := 1; i < slice.Len(); i++ {
+		prev := slice.Index(i - 1)
+		elem := slice.Index(i)
+
+		var inOrder bool
+
+		var err error
+
+		if c.Descending() {
+			inOrder, err = elem.LessEqual(prev)
+		} else {
+			inOrder, err = elem.GreaterEqual(prev)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !inOrder {
+			return &ViolationError{
+				Constraint: c,
+				Value:      v,
+				Reason:     fmt.Sprintf("element %d is out of order", i),
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *Sorted) String() string { return fmt.Sprintf("sorted(x, %s)", c.val.Value()) }
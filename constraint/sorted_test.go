@@ -0,0 +1,42 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSorted(t *testing.T) {
+	asc := constraint.NewSortedAscending()
+	desc := constraint.NewSortedDescending()
+
+	assert.Equal(t, asc.Type(), constraint.TypeSorted)
+	assert.Equal(t, constraint.SortAscending, asc.Param().(value.Single).Value())
+	assert.False(t, asc.Descending())
+	assert.True(t, desc.Descending())
+	assert.Equal(t, "sorted(x, descending)", desc.String())
+
+	result, err := asc.CompatibleWith(constraint.NewUnique())
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	result, err = asc.CompatibleWith(desc)
+
+	assert.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestSortedCheck(t *testing.T) {
+	asc := constraint.NewSortedAscending()
+	desc := constraint.NewSortedDescending()
+
+	assert.NoError(t, asc.Check(value.NewIntSlice()))
+	assert.NoError(t, asc.Check(value.NewIntSlice(1, 2, 2, 5)))
+	assert.Error(t, asc.Check(value.NewIntSlice(1, 3, 2)))
+	assert.NoError(t, desc.Check(value.NewFloatSlice(3.5, 2.5, 2.5)))
+	assert.Error(t, desc.Check(value.NewFloatSlice(2.5, 3.5)))
+	assert.Error(t, asc.Check(value.NewInt(1)))
+}
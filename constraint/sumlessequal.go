@@ -0,0 +1,103 @@
+package constraint
+
+import (
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// SumLessEqual restricts the sum of numeric slice elements to be less than
+// or equal to the parameter
+type SumLessEqual struct {
+	val value.Single
+}
+
+// NewSumLessEqual makes a new SumLessEqual constraint
+func NewSumLessEqual(val value.Single) *SumLessEqual {
+	return &SumLessEqual{val: val}
+}
+
+// Type returns the constraint type.
+func (c *SumLessEqual) Type() Type { return TypeSumLessEqual }
+
+// Param returns the constraint parameter.
+func (c *SumLessEqual) Param() value.Value { return c.val }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// Returns a non-nil error in case of failure.
+func (c *SumLessEqual) CompatibleWith(c2 Constraint) (bool, error) {
+	if c2.Type() == TypeSumLessEqual {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error if the sum of slice elements is greater than the parameter.
+func (c *SumLessEqual) Check(v value.Value) error {
+	slice, ok := v.(value.Slice)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	sum, overflow := sumOf(slice)
+	if overflow {
+		return &ViolationError{Constraint: c, Value: v, Reason: "sum overflows"}
+	}
+
+	if sum == nil {
+		return notApplicable(c, v)
+	}
+
+	ok, err := sum.LessEqual(c.val)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return &ViolationError{Constraint: c, Value: v, Reason: "sum is " + FormatValue(sum)}
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *SumLessEqual) String() string { return "sum(x) <= " + FormatValue(c.val) }
+
+// sumOf adds up the elements of a numeric slice.
+// Returns nil if the slice is not numeric, or true if an integer sum overflows.
+func sumOf(s value.Slice) (value.Single, bool) {
+	switch vals := s.Slice().(type) {
+	case []int64:
+		var sum int64
+		for _, val := range vals {
+			next := sum + val
+			if (val > 0 && next < sum) || (val < 0 && next > sum) {
+				return nil, true
+			}
+
+			sum = next
+		}
+
+		return value.NewInt(sum), false
+	case []uint64:
+		var sum uint64
+		for _, val := range vals {
+			next := sum + val
+			if next < sum {
+				return nil, true
+			}
+
+			sum = next
+		}
+
+		return value.NewUInt(sum), false
+	case []float64:
+		var sum float64
+		for _, val := range vals {
+			sum += val
+		}
+
+		return value.NewFloat(sum), false
+	}
+
+	return nil, false
+}
@@ -0,0 +1,59 @@
+package constraint_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumLessEqual(t *testing.T) {
+	c := constraint.NewSumLessEqual(value.NewFloat(1.0))
+
+	assert.Equal(t, c.Type(), constraint.TypeSumLessEqual)
+	assert.Equal(t, "sum(x) <= 1", c.String())
+
+	result, err := c.CompatibleWith(constraint.NewMaxLen(2))
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	result, err = c.CompatibleWith(constraint.NewSumLessEqual(value.NewFloat(2.0)))
+
+	assert.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestSumLessEqualCheck(t *testing.T) {
+	c := constraint.NewSumLessEqual(value.NewUInt(100))
+
+	assert.NoError(t, c.Check(value.NewUIntSlice()))
+	assert.NoError(t, c.Check(value.NewUIntSlice(50, 50)))
+	assert.Error(t, c.Check(value.NewUIntSlice(50, 51)))
+	assert.Error(t, c.Check(value.NewIntSlice(1)))
+	assert.Error(t, c.Check(value.NewStringSlice("a")))
+	assert.Error(t, c.Check(value.NewUInt(1)))
+
+}
+
+func TestSumLessEqualOverflow(t *testing.T) {
+	uc := constraint.NewSumLessEqual(value.NewUInt(10))
+
+	assert.Error(t, uc.Check(value.NewUIntSlice(math.MaxUint64, 2)))
+
+	ic := constraint.NewSumLessEqual(value.NewInt(10))
+
+	assert.Error(t, ic.Check(value.NewIntSlice(math.MaxInt64, 1)))
+	assert.Error(t, ic.Check(value.NewIntSlice(math.MinInt64, -1)))
+	assert.NoError(t, ic.Check(value.NewIntSlice(math.MaxInt64, -math.MaxInt64)))
+
+	var violationErr *constraint.ViolationError
+
+	err := uc.Check(value.NewUIntSlice(math.MaxUint64, 2))
+
+	assert.True(t, errors.As(err, &violationErr))
+	assert.Equal(t, "sum overflows", violationErr.Reason)
+}
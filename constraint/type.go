@@ -20,6 +20,30 @@ const (
 	TypeMinLen
 	// TypeMaxLen indicates a maximum length for array value types
 	TypeMaxLen
+	// TypeAllOf indicates that all wrapped constraints must be satisfied
+	TypeAllOf
+	// TypeAnyOf indicates that at least one wrapped constraint must be satisfied
+	TypeAnyOf
+	// TypeNot indicates that the wrapped constraint must not be satisfied
+	TypeNot
+	// TypeNoneOf indicates an excluded set of values
+	TypeNoneOf
+	// TypeEach indicates constraints that apply to each slice element
+	TypeEach
+	// TypeUnique indicates a slice without duplicate elements
+	TypeUnique
+	// TypeSorted indicates a slice in ascending or descending order
+	TypeSorted
+	// TypeContains indicates a slice that includes a required value
+	TypeContains
+	// TypeSumLessEqual indicates a maximum sum of numeric slice elements
+	TypeSumLessEqual
+	// TypeMultipleOf indicates a numeric value that is a multiple of a step
+	TypeMultipleOf
+	// TypeFunc indicates a custom check function
+	TypeFunc
+	// TypePattern indicates a string that matches a regular expression
+	TypePattern
 
 	// DefaultStr represents an optional default value
 	DefaultStr = "default"
@@ -37,12 +61,38 @@ const (
 	LessEqualStr = "lessEqual"
 	// OneOfStr represents an enumerated value
 	OneOfStr = "oneOf"
+	// NoneOfStr represents an excluded set of values
+	NoneOfStr = "noneOf"
+	// EachStr represents constraints that apply to each slice element
+	EachStr = "each"
+	// UniqueStr represents a slice without duplicate elements
+	UniqueStr = "unique"
+	// SortedStr represents a slice in ascending or descending order
+	SortedStr = "sorted"
+	// ContainsStr represents a slice that includes a required value
+	ContainsStr = "contains"
+	// SumLessEqualStr represents a maximum sum of numeric slice elements
+	SumLessEqualStr = "sumLessEqual"
+	// MultipleOfStr represents a numeric value that is a multiple of a step
+	MultipleOfStr = "multipleOf"
+	// FuncStr represents a custom check function
+	FuncStr = "func"
+	// AllOfStr represents a conjunction of constraints
+	AllOfStr = "allOf"
+	// AnyOfStr represents a disjunction of constraints
+	AnyOfStr = "anyOf"
+	// NotStr represents a negated constraint
+	NotStr = "not"
+	// PatternStr represents a string that matches a regular expression
+	PatternStr = "pattern"
 )
 
 // AllTypes returns all of the option types.
 func AllTypes() []Type {
 	return []Type{
-		TypeGreater, TypeGreaterEqual, TypeLess, TypeLessEqual, TypeOneOf, TypeMinLen, TypeMaxLen}
+		TypeGreater, TypeGreaterEqual, TypeLess, TypeLessEqual, TypeOneOf, TypeMinLen, TypeMaxLen,
+		TypeAllOf, TypeAnyOf, TypeNot, TypeNoneOf, TypeEach, TypeUnique, TypeSorted, TypeContains,
+		TypeSumLessEqual, TypeMultipleOf, TypeFunc, TypePattern}
 }
 
 // Valid returns if the current type is one of AllTypes
@@ -73,21 +123,60 @@ func (t Type) String() string {
 		return LessEqualStr
 	case TypeOneOf:
 		return OneOfStr
+	case TypeNoneOf:
+		return NoneOfStr
+	case TypeEach:
+		return EachStr
+	case TypeUnique:
+		return UniqueStr
+	case TypeSorted:
+		return SortedStr
+	case TypeContains:
+		return ContainsStr
+	case TypeSumLessEqual:
+		return SumLessEqualStr
+	case TypeMultipleOf:
+		return MultipleOfStr
+	case TypeFunc:
+		return FuncStr
+	case TypeAllOf:
+		return AllOfStr
+	case TypeAnyOf:
+		return AnyOfStr
+	case TypeNot:
+		return NotStr
+	case TypePattern:
+		return PatternStr
 	}
 
 	return ""
 }
 
 // ApplicableTo returns true if the current option is applicable to the given value type.
+// Composite and func types are applicable to any value, but the constraints they
+// wrap or the types they declare may not be (see the ApplicableTo function).
 func (t Type) ApplicableTo(v value.Value) bool {
 	switch t {
-	case TypeGreater, TypeGreaterEqual, TypeLess, TypeLessEqual:
+	case TypeGreater, TypeGreaterEqual, TypeLess, TypeLessEqual, TypeNoneOf,
+		TypeFunc, TypeAllOf, TypeAnyOf, TypeNot:
 		return true
 	case TypeMinLen, TypeMaxLen:
 		return v.IsSlice() || (v.Type() == value.TypeString)
 	case TypeOneOf:
 		return !v.IsSlice()
+	case TypeEach, TypeUnique, TypeSorted, TypeContains:
+		return v.IsSlice()
+	case TypeSumLessEqual:
+		return v.IsSlice() && isNumeric(v.Type())
+	case TypeMultipleOf:
+		return isNumeric(v.Type())
+	case TypePattern:
+		return !v.IsSlice() && v.Type() == value.TypeString
 	}
 
 	return false
 }
+
+func isNumeric(t value.Type) bool {
+	return t == value.TypeInt || t == value.TypeUInt || t == value.TypeFloat
+}
@@ -28,6 +28,16 @@ func TestKnownTypes(t *testing.T) {
 	}
 }
 
+func TestTypeValuesAreStable(t *testing.T) {
+	// new types are added at the end, so existing values do not change
+	for i, typ := range constraint.AllTypes() {
+		assert.Equal(t, constraint.Type(i), typ, typ.String())
+	}
+
+	assert.Equal(t, constraint.Type(7), constraint.TypeAllOf)
+	assert.Equal(t, constraint.Type(18), constraint.TypePattern)
+}
+
 func TestUnknownType(t *testing.T) {
 	typ := constraint.Type(-1)
 
@@ -66,6 +76,56 @@ func TestApplicableToWithOneOf(t *testing.T) {
 	}
 }
 
+func TestApplicableToWithNoneOf(t *testing.T) {
+	for _, val := range allSingleVals {
+		assert.True(t, constraint.TypeNoneOf.ApplicableTo(val))
+	}
+
+	for _, val := range allSliceVals {
+		assert.True(t, constraint.TypeNoneOf.ApplicableTo(val))
+	}
+}
+
+func TestApplicableToWithSliceOnly(t *testing.T) {
+	cTypes := []constraint.Type{
+		constraint.TypeEach, constraint.TypeUnique,
+		constraint.TypeSorted, constraint.TypeContains,
+	}
+
+	for _, cType := range cTypes {
+		for _, val := range allSingleVals {
+			assert.False(t, cType.ApplicableTo(val))
+		}
+
+		for _, val := range allSliceVals {
+			assert.True(t, cType.ApplicableTo(val))
+		}
+	}
+
+	assert.True(t, constraint.TypeSumLessEqual.ApplicableTo(value.NewIntSlice()))
+	assert.False(t, constraint.TypeSumLessEqual.ApplicableTo(value.NewInt(0)))
+	assert.False(t, constraint.TypeSumLessEqual.ApplicableTo(value.NewStringSlice()))
+}
+
+func TestApplicableToWithMultipleOf(t *testing.T) {
+	numeric := []value.Value{
+		value.NewUInt(0), value.NewInt(0), value.NewFloat(0.0),
+		value.NewUIntSlice(), value.NewIntSlice(), value.NewFloatSlice(),
+	}
+	nonNumeric := []value.Value{
+		value.NewString(""), value.NewBool(false),
+		value.NewStringSlice(), value.NewBoolSlice(),
+	}
+
+	for _, val := range numeric {
+		assert.True(t, constraint.TypeMultipleOf.ApplicableTo(val))
+	}
+
+	for _, val := range nonNumeric {
+		assert.False(t, constraint.TypeMultipleOf.ApplicableTo(val))
+	}
+}
+
 func TestApplicableToWithLen(t *testing.T) {
 	allSingleValsExceptString := []value.Single{
 		value.NewUInt(0), value.NewInt(0), value.NewFloat(0.0), value.NewBool(false),
@@ -85,3 +145,10 @@ func TestApplicableToWithLen(t *testing.T) {
 		assert.True(t, constraint.TypeMaxLen.ApplicableTo(val))
 	}
 }
+
+func TestApplicableToWithPattern(t *testing.T) {
+	assert.True(t, constraint.TypePattern.ApplicableTo(value.NewString("")))
+	assert.False(t, constraint.TypePattern.ApplicableTo(value.NewStringSlice()))
+	assert.False(t, constraint.TypePattern.ApplicableTo(value.NewInt(0)))
+	assert.False(t, constraint.TypePattern.ApplicableTo(value.NewBoolSlice()))
+}
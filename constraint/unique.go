@@ -0,0 +1,63 @@
+package constraint
+
+import (
+	"fmt"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// Unique restricts a slice value to have no duplicate elements
+type Unique struct{}
+
+// NewUnique makes a new Unique constraint
+func NewUnique() *Unique {
+	return &Unique{}
+}
+
+// Type returns the constraint type.
+func (c *Unique) Type() Type { return TypeUnique }
+
+// Param returns nil, since there is no parameter.
+func (c *Unique) Param() value.Value { return nil }
+
+// CompatibleWith returns true if the given constraint is compatible with the current one.
+// Returns a non-nil error in case of failure.
+func (c *Unique) CompatibleWith(c2 Constraint) (bool, error) {
+	if c2.Type() == TypeUnique {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Check returns a non-nil error naming the first duplicate slice element.
+func (c *Unique) Check(v value.Value) error {
+	slice, ok := v.(value.Slice)
+	if !ok {
+		return notApplicable(c, v)
+	}
+
+	for i := 1; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+
+		for j := 0; j < i; j++ {
+			equal, err := elem.Equal(slice.Index(j))
+			if err != nil {
+				return err
+			}
+
+			if equal {
+				return &ViolationError{
+					Constraint: c,
+					Value:      elem,
+					Reason:     fmt.Sprintf("duplicated at elements %d and %d", j, i),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// String returns a readable description of the constraint.
+func (c *Unique) String() string { return "unique(x)" }
@@ -0,0 +1,40 @@
+package constraint_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnique(t *testing.T) {
+	c := constraint.NewUnique()
+
+	assert.Equal(t, c.Type(), constraint.TypeUnique)
+	assert.Nil(t, c.Param())
+	assert.Equal(t, "unique(x)", c.String())
+
+	result, err := c.CompatibleWith(constraint.NewMaxLen(2))
+
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	result, err = c.CompatibleWith(constraint.NewUnique())
+
+	assert.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestUniqueCheck(t *testing.T) {
+	c := constraint.NewUnique()
+
+	assert.NoError(t, c.Check(value.NewStringSlice()))
+	assert.NoError(t, c.Check(value.NewStringSlice("us", "eu")))
+
+	err := c.Check(value.NewStringSlice("us", "eu", "us"))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "us")
+	assert.Error(t, c.Check(value.NewString("us")))
+}
@@ -0,0 +1,84 @@
+package setting
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deprecation marks an element as deprecated.
+type Deprecation struct {
+	// Since is the version where the element was deprecated
+	Since string
+	// RemovedIn is the version where the element will be removed
+	RemovedIn string
+	// Replacement is the path of the element to use instead, if any
+	Replacement []string
+	// Message gives optional details
+	Message string
+}
+
+// Alias allows an element to be loaded using an old path, after it has been
+// renamed or moved. Paths are relative to the group that has the alias.
+type Alias struct {
+	Old []string
+	New []string
+	// Since is the version where the old path was deprecated
+	Since string
+	// RemovedIn is the version where the old path will no longer be accepted
+	RemovedIn string
+}
+
+// DeprecationWarning is collected when loading a deprecated element or an alias.
+type DeprecationWarning struct {
+	// Path is the deprecated path that was used
+	Path []string
+	// Replacement is the path to use instead, if any
+	Replacement []string
+	Since       string
+	RemovedIn   string
+	Message     string
+}
+
+// String returns a readable warning message.
+func (w *DeprecationWarning) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s is deprecated", strings.Join(w.Path, "."))
+
+	if w.Since != "" {
+		fmt.Fprintf(&b, " since %s", w.Since)
+	}
+
+	if w.RemovedIn != "" {
+		fmt.Fprintf(&b, " and will be removed in %s", w.RemovedIn)
+	}
+
+	if len(w.Replacement) > 0 {
+		fmt.Fprintf(&b, "; use %s instead", strings.Join(w.Replacement, "."))
+	}
+
+	if w.Message != "" {
+		fmt.Fprintf(&b, " (%s)", w.Message)
+	}
+
+	return b.String()
+}
+
+func (a *Alias) warning(path []string) *DeprecationWarning {
+	return &DeprecationWarning{
+		Path:        appendPath(path, a.Old...),
+		Replacement: appendPath(path, a.New...),
+		Since:       a.Since,
+		RemovedIn:   a.RemovedIn,
+	}
+}
+
+func (d *Deprecation) warning(path []string) *DeprecationWarning {
+	return &DeprecationWarning{
+		Path:        path,
+		Replacement: d.Replacement,
+		Since:       d.Since,
+		RemovedIn:   d.RemovedIn,
+		Message:     d.Message,
+	}
+}
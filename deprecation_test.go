@@ -0,0 +1,112 @@
+package setting_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/stretchr/testify/assert"
+)
+
+type testListener struct {
+	Port int64  `setting:"port"`
+	Host string `setting:"host"`
+}
+
+type testService struct {
+	Listener testListener `setting:"listener"`
+	Workers  int64        `setting:"workers"`
+}
+
+func newTestServiceGroup(t *testing.T) (*setting.Group, *testService) {
+	s := &testService{}
+	g, err := setting.FromStruct(s)
+
+	assert.NoError(t, err)
+
+	g.Aliases = []*setting.Alias{
+		{Old: []string{"port"}, New: []string{"listener", "port"}, Since: "1.4", RemovedIn: "2.0"},
+		{Old: []string{"legacy", "host"}, New: []string{"listener", "host"}},
+	}
+
+	return g, s
+}
+
+func TestLoadWithAlias(t *testing.T) {
+	g, s := newTestServiceGroup(t)
+	doc := map[string]interface{}{
+		"port":    8080,
+		"legacy":  map[string]interface{}{"host": "example.com"},
+		"workers": 2,
+	}
+
+	assert.NoError(t, g.Load(doc))
+	assert.Equal(t, int64(8080), s.Listener.Port)
+	assert.Equal(t, "example.com", s.Listener.Host)
+
+	if assert.Len(t, g.Warnings, 2) {
+		w := g.Warnings[0]
+
+		assert.Equal(t, []string{"port"}, w.Path)
+		assert.Equal(t, []string{"listener", "port"}, w.Replacement)
+		assert.Equal(t, "2.0", w.RemovedIn)
+		assert.Equal(t,
+			"port is deprecated since 1.4 and will be removed in 2.0; use listener.port instead",
+			w.String())
+	}
+
+	// the given document is not modified
+	assert.Contains(t, doc, "port")
+
+	assert.NoError(t, g.Load(map[string]interface{}{"workers": 3}))
+	assert.Empty(t, g.Warnings)
+}
+
+func TestLoadWithAliasAndNewPath(t *testing.T) {
+	g, _ := newTestServiceGroup(t)
+	doc := map[string]interface{}{
+		"port":     8080,
+		"listener": map[string]interface{}{"port": 8081},
+	}
+
+	assert.Error(t, g.Load(doc))
+}
+
+func TestLoadDeprecatedElement(t *testing.T) {
+	g, _ := newTestServiceGroup(t)
+	g.Elements["workers"].Deprecated = &setting.Deprecation{
+		RemovedIn: "3.0",
+		Message:   "workers are sized automatically",
+	}
+
+	assert.NoError(t, g.Load(map[string]interface{}{"workers": 4}))
+
+	if assert.Len(t, g.Warnings, 1) {
+		assert.Equal(t,
+			"workers is deprecated and will be removed in 3.0 (workers are sized automatically)",
+			g.Warnings[0].String())
+	}
+}
+
+func TestAliasPaths(t *testing.T) {
+	g, s := newTestServiceGroup(t)
+
+	assert.NoError(t, g.Set("port", "8080"))
+	assert.Equal(t, int64(8080), s.Listener.Port)
+
+	port, err := g.GetInt("port")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8080), port)
+
+	assert.NoError(t, g.LoadFlat(map[string]string{"legacy.host": "example.com", "workers": "2"}))
+	assert.Equal(t, "example.com", s.Listener.Host)
+
+	if assert.Len(t, g.Warnings, 1) {
+		assert.Equal(t, []string{"legacy", "host"}, g.Warnings[0].Path)
+	}
+
+	assert.NoError(t, g.LoadEnv("APP", []string{"APP_PORT=9090", "APP_LEGACY_HOST=a.com"}))
+	assert.Equal(t, int64(9090), s.Listener.Port)
+	assert.Equal(t, "a.com", s.Listener.Host)
+	assert.Len(t, g.Warnings, 2)
+}
@@ -0,0 +1,372 @@
+package setting
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// DefaultDocsTitle is the document title used by Group.Markdown and
+// Group.HTML if none is given.
+const DefaultDocsTitle = "Configuration reference"
+
+// DocsOptions configures Group.Markdown and Group.HTML.
+type DocsOptions struct {
+	// Title is the document title, or empty for DefaultDocsTitle
+	Title string
+	// EnvPrefix is the prefix of environment variable names (see EnvName)
+	EnvPrefix string
+}
+
+// docSection is a group of settings with the same parent path.
+type docSection struct {
+	path  []string
+	infos []*settingInfo
+	// anchor is the HTML id of the section, and infoAnchors those of the settings
+	anchor      string
+	infoAnchors []string
+}
+
+func (s *docSection) title() string {
+	if len(s.path) == 0 {
+		return "General"
+	}
+
+	return strings.Join(s.path, ".")
+}
+
+// optional returns the path of the enclosing optional subgroup, if any.
+func (s *docSection) optional() []string {
+	if len(s.infos) == 0 {
+		return nil
+	}
+
+	return s.infos[0].optional
+}
+
+// docField is a row in the table for a setting.
+type docField struct {
+	name  string
+	value string
+	// code values are shown in code formatting
+	code bool
+}
+
+// docSections groups the settings by parent path, in order of first appearance.
+// There is also a section for each parent of a section, which may be empty.
+func (g *Group) docSections() []*docSection {
+	sections := []*docSection{}
+	byPath := map[string]*docSection{}
+
+	section := func(path []string) *docSection {
+		key := strings.Join(path, "\x00")
+
+		s, found := byPath[key]
+		if !found {
+			s = &docSection{path: path}
+			byPath[key] = s
+			sections = append(sections, s)
+		}
+
+		return s
+	}
+
+	for _, info := range g.settingInfos() {
+		parent := info.path[:len(info.path)-1]
+
+		for i := 1; i < len(parent); i++ {
+			section(parent[:i])
+		}
+
+		s := section(parent)
+		s.infos = append(s.infos, info)
+	}
+
+	assignAnchors(sections)
+
+	return sections
+}
+
+// assignAnchors gives each section and setting a unique HTML id, in document
+// order. Paths that give the same id, such as "a.b" and "a_b", get a numbered
+// suffix after the first.
+func assignAnchors(sections []*docSection) {
+	used := map[string]bool{}
+
+	unique := func(id string) string {
+		if id == "" {
+			id = "setting"
+		}
+
+		unique := id
+
+		for n := 2; used[unique]; n++ {
+			unique = fmt.Sprintf("%s-%d", id, n)
+		}
+
+		used[unique] = true
+
+		return unique
+	}
+
+	for _, section := range sections {
+		if len(section.path) == 0 {
+			section.anchor = unique("general")
+		} else {
+			section.anchor = unique(anchorName(strings.Join(section.path, ".")))
+		}
+
+		section.infoAnchors = make([]string, len(section.infos))
+
+		for i, info := range section.infos {
+			section.infoAnchors[i] = unique(anchorName(info.pathString()))
+		}
+	}
+}
+
+// docFields returns the table rows for a setting. Sensitive values and
+// examples are not shown.
+func (info *settingInfo) docFields(envPrefix string) []docField {
+	elem := info.elem
+	fields := []docField{
+		{name: "Type", value: typeName(elem.Value), code: true},
+		{name: "Default", value: elem.String(), code: true},
+	}
+
+	if unit := elem.Metadata.Unit; unit != "" {
+		fields = append(fields, docField{name: "Unit", value: unit})
+	}
+
+	if constraints := describeConstraints(elem.Constraints); len(constraints) > 0 {
+		fields = append(fields, docField{
+			name: "Constraints", value: strings.Join(constraints, ", "), code: true})
+	}
+
+	if len(elem.Metadata.Examples) > 0 && !elem.Sensitive {
+		fields = append(fields, docField{
+			name: "Examples", value: strings.Join(elem.Metadata.Examples, "; "), code: true})
+	}
+
+	if elem.Required {
+		fields = append(fields, docField{name: "Required", value: "yes"})
+	}
+
+	if elem.Sensitive {
+		fields = append(fields, docField{name: "Sensitive", value: "yes"})
+	}
+
+	if since := elem.Metadata.Since; since != "" {
+		fields = append(fields, docField{name: "Since", value: since})
+	}
+
+	for _, name := range elem.Metadata.TagNames() {
+		fields = append(fields, docField{name: name, value: elem.Metadata.Tags[name]})
+	}
+
+	return append(fields,
+		docField{name: "Env", value: info.envName(envPrefix), code: true},
+		docField{name: "Flag", value: info.flagName(), code: true})
+}
+
+// Markdown returns a reference document in Markdown, with a table of contents
+// following the subgroups, lists and maps, and a section for each setting
+// (see Help). The output only depends on the group, so it can be committed
+// and checked for changes.
+func (g *Group) Markdown(opts DocsOptions) string {
+	var b strings.Builder
+
+	sections := g.docSections()
+
+	fmt.Fprintf(&b, "# %s\n\n## Contents\n\n", docsTitle(opts))
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "%s- [%s](#%s)\n", strings.Repeat("  ", tocDepth(section)),
+			markdownEscape(section.title()), section.anchor)
+	}
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n## %s\n", section.anchor, markdownEscape(section.title()))
+
+		if optional := section.optional(); optional != nil {
+			fmt.Fprintf(&b, "\nOnly used if %s is present.\n", markdownCode(strings.Join(optional, ".")))
+		}
+
+		for i, info := range section.infos {
+			path := info.pathString()
+
+			fmt.Fprintf(&b, "\n<a id=\"%s\"></a>\n\n### %s\n\n", section.infoAnchors[i], markdownEscape(path))
+
+			if desc := info.elem.Metadata.Description; desc != "" {
+				fmt.Fprintf(&b, "%s\n\n", markdownEscape(desc))
+			}
+
+			if info.elem.Deprecated != nil {
+				fmt.Fprintf(&b, "> **Deprecated:** %s\n\n",
+					markdownEscape(info.elem.Deprecated.warning(info.path).String()))
+			}
+
+			b.WriteString("| Field | Value |\n| --- | --- |\n")
+
+			for _, field := range info.docFields(opts.EnvPrefix) {
+				val := markdownEscape(field.value)
+				if field.code {
+					val = markdownCode(field.value)
+				}
+
+				fmt.Fprintf(&b, "| %s | %s |\n", markdownEscape(field.name),
+					strings.ReplaceAll(val, "|", `\|`))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// HTML returns a standalone HTML page with the same content as Markdown.
+func (g *Group) HTML(opts DocsOptions) string {
+	var b strings.Builder
+
+	title := html.EscapeString(docsTitle(opts))
+	sections := g.docSections()
+
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: auto; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<h2>Contents</h2>
+<ul>
+`, title, title)
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "<li style=\"margin-left: %dem\"><a href=\"#%s\">%s</a></li>\n",
+			2*tocDepth(section), section.anchor, html.EscapeString(section.title()))
+	}
+
+	b.WriteString("</ul>\n")
+
+	for _, section := range sections {
+		fmt.Fprintf(&b, "<h2 id=\"%s\">%s</h2>\n", section.anchor, html.EscapeString(section.title()))
+
+		if optional := section.optional(); optional != nil {
+			fmt.Fprintf(&b, "<p>Only used if <code>%s</code> is present.</p>\n",
+				html.EscapeString(strings.Join(optional, ".")))
+		}
+
+		for i, info := range section.infos {
+			path := info.pathString()
+
+			fmt.Fprintf(&b, "<h3 id=\"%s\">%s</h3>\n", section.infoAnchors[i], html.EscapeString(path))
+
+			if desc := info.elem.Metadata.Description; desc != "" {
+				fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(desc))
+			}
+
+			if info.elem.Deprecated != nil {
+				fmt.Fprintf(&b, "<p><strong>Deprecated:</strong> %s</p>\n",
+					html.EscapeString(info.elem.Deprecated.warning(info.path).String()))
+			}
+
+			b.WriteString("<table>\n")
+
+			for _, field := range info.docFields(opts.EnvPrefix) {
+				val := html.EscapeString(field.value)
+				if field.code {
+					val = "<code>" + val + "</code>"
+				}
+
+				fmt.Fprintf(&b, "<tr><th>%s</th><td>%s</td></tr>\n", html.EscapeString(field.name), val)
+			}
+
+			b.WriteString("</table>\n")
+		}
+	}
+
+	b.WriteString("</body>\n</html>\n")
+
+	return b.String()
+}
+
+func docsTitle(opts DocsOptions) string {
+	if opts.Title == "" {
+		return DefaultDocsTitle
+	}
+
+	return opts.Title
+}
+
+// tocDepth returns the nesting level of a section in the table of contents.
+func tocDepth(s *docSection) int {
+	if len(s.path) == 0 {
+		return 0
+	}
+
+	return len(s.path) - 1
+}
+
+// anchorName makes an HTML id from a path, using lower-case letters, digits
+// and dashes.
+func anchorName(path string) string {
+	var b strings.Builder
+
+	dash := false
+
+	for _, r := range strings.ToLower(path) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+
+			b.WriteRune(r)
+
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+
+	return b.String()
+}
+
+// markdownEscape escapes characters with special meaning in Markdown text.
+func markdownEscape(str string) string {
+	return strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `*`, `\*`, `_`, `\_`, "`", "\\`",
+		`<`, `\<`, `>`, `\>`, `#`, `\#`).Replace(str)
+}
+
+// markdownCode makes a Markdown code span. Since backslashes do not escape in
+// code spans, it is delimited by more backticks than the longest run of
+// backticks in the string.
+func markdownCode(str string) string {
+	longest, run := 0, 0
+
+	for _, r := range str {
+		if r != '`' {
+			run = 0
+
+			continue
+		}
+
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+
+	fence := strings.Repeat("`", longest+1)
+
+	if strings.HasPrefix(str, "`") || strings.HasSuffix(str, "`") {
+		return fence + " " + str + " " + fence
+	}
+
+	return fence + str + fence
+}
@@ -0,0 +1,135 @@
+package setting_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDocsTLS struct {
+	Port int64 `setting:"port" since:"1.1"`
+}
+
+type testDocsServer struct {
+	TLS *testDocsTLS `setting:"tls"`
+}
+
+type testDocs struct {
+	Level    string         `setting:"level" desc:"Log level" example:"debug;info"`
+	Password string         `setting:"password" sensitive:"true" example:"hunter2"`
+	Server   testDocsServer `setting:"server"`
+}
+
+func newTestDocsGroup(t *testing.T) *setting.Group {
+	g, err := setting.FromStruct(&testDocs{Level: "info", Password: "secret"})
+
+	assert.NoError(t, err)
+
+	g.Elements["level"].Constraints = []constraint.Constraint{
+		constraint.NewOneOf(value.NewStringSlice("debug", "info", "a|b")),
+	}
+	g.Elements["level"].Deprecated = &setting.Deprecation{Replacement: []string{"log", "level"}}
+
+	return g
+}
+
+func TestMarkdown(t *testing.T) {
+	g := newTestDocsGroup(t)
+
+	expected := "# Config\n\n## Contents\n\n" +
+		"- [General](#general)\n" +
+		"- [server](#server)\n" +
+		"  - [server.tls](#server-tls)\n" +
+		"\n<a id=\"general\"></a>\n\n## General\n" +
+		"\n<a id=\"level\"></a>\n\n### level\n\n" +
+		"Log level\n\n" +
+		"> **Deprecated:** level is deprecated; use log.level instead\n\n" +
+		"| Field | Value |\n| --- | --- |\n" +
+		"| Type | `string` |\n" +
+		"| Default | `info` |\n" +
+		"| Constraints | `one of: debug, info, a\\|b` |\n" +
+		"| Examples | `debug; info` |\n" +
+		"| Env | `APP_LEVEL` |\n" +
+		"| Flag | `--level` |\n" +
+		"\n<a id=\"password\"></a>\n\n### password\n\n" +
+		"| Field | Value |\n| --- | --- |\n" +
+		"| Type | `string` |\n" +
+		"| Default | `******` |\n" +
+		"| Sensitive | yes |\n" +
+		"| Env | `APP_PASSWORD` |\n" +
+		"| Flag | `--password` |\n" +
+		"\n<a id=\"server\"></a>\n\n## server\n" +
+		"\n<a id=\"server-tls\"></a>\n\n## server.tls\n" +
+		"\nOnly used if `server.tls` is present.\n" +
+		"\n<a id=\"server-tls-port\"></a>\n\n### server.tls.port\n\n" +
+		"| Field | Value |\n| --- | --- |\n" +
+		"| Type | `int64` |\n" +
+		"| Default | `0` |\n" +
+		"| Since | 1.1 |\n" +
+		"| Env | `APP_SERVER_TLS_PORT` |\n" +
+		"| Flag | `--server.tls.port` |\n"
+
+	md := g.Markdown(setting.DocsOptions{Title: "Config", EnvPrefix: "APP"})
+
+	assert.Equal(t, expected, md)
+	assert.Equal(t, md, g.Markdown(setting.DocsOptions{Title: "Config", EnvPrefix: "APP"}))
+	assert.NotContains(t, md, "secret")
+	assert.NotContains(t, md, "hunter2")
+}
+
+func TestMarkdownLists(t *testing.T) {
+	g, _ := setting.FromStruct(&testProxy{})
+
+	md := g.Markdown(setting.DocsOptions{})
+
+	assert.True(t, strings.HasPrefix(md, "# "+setting.DefaultDocsTitle+"\n"))
+	assert.Contains(t, md, "- [upstreams\\[N\\]](#upstreams-n)\n")
+	assert.Contains(t, md, "### upstreams\\[N\\].host\n")
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"a_b": setting.NewElement(value.NewString("x`y")),
+		},
+		Subgroups: map[string]*setting.Group{
+			"a": {
+				Elements: map[string]*setting.Element{"b": setting.NewElement(value.NewString("`"))},
+			},
+		},
+	}
+	g.Elements["a_b"].Metadata.Description = "Use *only* <b>one</b> of [these](#x)"
+
+	md := g.Markdown(setting.DocsOptions{})
+
+	assert.Contains(t, md, "<a id=\"a-b\"></a>\n\n### a\\_b\n")
+	assert.Contains(t, md, "<a id=\"a-b-2\"></a>\n\n### a.b\n")
+	assert.Contains(t, md, "Use \\*only\\* \\<b\\>one\\</b\\> of \\[these\\](\\#x)\n")
+	assert.Contains(t, md, "| Default | ``x`y`` |\n")
+	assert.Contains(t, md, "| Default | `` ` `` |\n")
+
+	page := g.HTML(setting.DocsOptions{})
+
+	assert.Contains(t, page, `<h3 id="a-b-2">a.b</h3>`)
+}
+
+func TestHTML(t *testing.T) {
+	g := newTestDocsGroup(t)
+
+	page := g.HTML(setting.DocsOptions{})
+
+	assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>\n"))
+	assert.Contains(t, page, "<title>Configuration reference</title>")
+	assert.Contains(t, page, `<li style="margin-left: 2em"><a href="#server-tls">server.tls</a></li>`)
+	assert.Contains(t, page, `<h3 id="server-tls-port">server.tls.port</h3>`)
+	assert.Contains(t, page, "<tr><th>Constraints</th><td><code>one of: debug, info, a|b</code></td></tr>")
+	assert.Contains(t, page, "<tr><th>Default</th><td><code>******</code></td></tr>")
+	assert.Contains(t, page, "<p><strong>Deprecated:</strong> level is deprecated; use log.level instead</p>")
+	assert.Contains(t, page, "<p>Only used if <code>server.tls</code> is present.</p>")
+	assert.NotContains(t, page, "secret")
+	assert.True(t, strings.HasSuffix(page, "</body>\n</html>\n"))
+}
@@ -0,0 +1,144 @@
+package setting
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// copyDocument makes a deep copy of the nested maps and lists in a raw document.
+// Other values are shared.
+func copyDocument(doc map[string]interface{}) map[string]interface{} {
+	docCopy := make(map[string]interface{}, len(doc))
+
+	for key, raw := range doc {
+		docCopy[key] = copyRaw(raw)
+	}
+
+	return docCopy
+}
+
+func copyRaw(raw interface{}) interface{} {
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		return copyDocument(raw)
+	case []interface{}:
+		items := make([]interface{}, len(raw))
+		for i, item := range raw {
+			items[i] = copyRaw(item)
+		}
+
+		return items
+	}
+
+	return raw
+}
+
+// documentGet looks up a raw value by path.
+// Returns false if it is not found.
+func documentGet(doc map[string]interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+
+	for _, key := range path[:len(path)-1] {
+		subdoc, ok := doc[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		doc = subdoc
+	}
+
+	raw, found := doc[path[len(path)-1]]
+
+	return raw, found
+}
+
+// documentSet sets a raw value by path, making nested maps as needed.
+// Returns false if a non-map value is in the way.
+func documentSet(doc map[string]interface{}, path []string, raw interface{}) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	for _, key := range path[:len(path)-1] {
+		existing, found := doc[key]
+		if !found {
+			existing = map[string]interface{}{}
+			doc[key] = existing
+		}
+
+		subdoc, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+
+		doc = subdoc
+	}
+
+	doc[path[len(path)-1]] = raw
+
+	return true
+}
+
+// documentDelete removes a raw value by path, if present. Nested maps that
+// become empty are also removed.
+func documentDelete(doc map[string]interface{}, path []string) {
+	switch len(path) {
+	case 0:
+		return
+	case 1:
+		delete(doc, path[0])
+		return
+	}
+
+	subdoc, ok := doc[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	documentDelete(subdoc, path[1:])
+
+	if len(subdoc) == 0 {
+		delete(doc, path[0])
+	}
+}
+
+// readDocumentFile reads a raw document from a JSON or YAML file, by the
+// .json, .yaml or .yml extension.
+// Returns a non-nil error if the file cannot be read or decoded, or the
+// extension is unknown.
+func readDocumentFile(path string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeDocument(path, data)
+}
+
+// decodeDocument decodes a raw document by the file extension, as in readDocumentFile.
+func decodeDocument(path string, data []byte) (map[string]interface{}, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return decodeJSON(bytes.NewReader(data))
+	case ".yaml", ".yml":
+		doc := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		}
+
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown config file extension %q", ext)
+	}
+}
+
+func isJSONFile(path string) bool {
+	return strings.ToLower(filepath.Ext(path)) == ".json"
+}
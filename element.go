@@ -1,18 +1,30 @@
 package setting
 
 import (
-	"fmt"
-
 	"github.com/jamestunnell/go-setting/constraint"
 	"github.com/jamestunnell/go-setting/value"
 )
 
 // Element is a setting group element, specifying value type and zero
-// or more constraints. If the default constraint is present then the element
-// is considered to be 'required'.
+// or more constraints. The element is considered to be 'required' if
+// Required is set.
 type Element struct {
 	Value       value.Value
 	Constraints []constraint.Constraint
+	// Required is schema and documentation metadata: it puts the element
+	// in the JSON Schema required list and marks it as required in help,
+	// docs and CompareSchemas. Load and Validate do not check it, so a
+	// missing required element keeps its current value.
+	Required bool
+	// Metadata describes the element
+	Metadata Metadata
+	// Sensitive elements have their value redacted in output and errors
+	Sensitive bool
+	// Deprecated is non-nil if the element is deprecated
+	Deprecated *Deprecation
+	// Messages optionally overrides catalog message templates, by locale.
+	// Templates for the empty locale apply to all locales.
+	Messages map[string]Messages
 }
 
 // New makes a new element.
@@ -23,36 +35,63 @@ func NewElement(
 	return &Element{Constraints: constraints, Value: val}
 }
 
+// Clone makes a copy of the element with a cloned value, which does not
+// share the backing pointer.
+func (e *Element) Clone() *Element {
+	clone := *e
+	clone.Value = e.Value.Clone()
+
+	return &clone
+}
+
 // CheckConstraints ensures that the constraints are all applicable to the element
 // value, and that all constraints are compatible with each other.
-// Returns non-nil error in case of failure.
+// Returns a non-nil *constraint.NotApplicableError or
+// *constraint.IncompatibleConstraintsError in case of failure.
 func (e *Element) CheckConstraints() error {
-	const (
-		notApplicableFmt = "constraint type %s is not applicable to value %v"
-		notCompatibleFmt = "constraint %v is not compatible with %v"
-	)
-
 	for i, c := range e.Constraints {
-		cType := c.Type()
-
-		if !cType.ApplicableTo(e.Value) {
-			return fmt.Errorf(notApplicableFmt, cType, e.Value)
+		if !constraint.ApplicableTo(c, e.Value) {
+			return &constraint.NotApplicableError{Constraint: c, Value: e.Value}
 		}
 
 		for j := i + 1; j < len(e.Constraints); j++ {
 			c2 := e.Constraints[j]
 
-			compatible, err := c.CompatibleWith(c2)
+			compatible, err := constraint.Compatible(c, c2)
 			if err != nil {
 				return err
 			}
 
 			if !compatible {
-				return fmt.Errorf(notCompatibleFmt, c2, c)
+				return &constraint.IncompatibleConstraintsError{
+					Constraints: []constraint.Constraint{c, c2},
+				}
 			}
 		}
 	}
 
+	compatible, err := constraint.CompatibleSet(e.Constraints)
+	if err != nil {
+		return err
+	}
+
+	if !compatible {
+		return &constraint.IncompatibleConstraintsError{Constraints: e.Constraints}
+	}
+
+	return nil
+}
+
+// Validate checks the element value against each of the constraints.
+// Returns non-nil error for the first constraint that is not satisfied,
+// which is a *constraint.ViolationError unless the constraint could not be checked.
+func (e *Element) Validate() error {
+	for _, c := range e.Constraints {
+		if err := c.Check(e.Value); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -66,3 +105,18 @@ func (e *Element) Constraint(cType constraint.Type) constraint.Constraint {
 	}
 	return nil
 }
+
+// String returns the element value as text, or value.Redacted if the
+// element is sensitive.
+func (e *Element) String() string {
+	if e.Sensitive {
+		return value.Redacted
+	}
+
+	return constraint.FormatValue(e.Value)
+}
+
+// RevealString returns the element value as text, even if the element is sensitive.
+func (e *Element) RevealString() string {
+	return constraint.FormatValue(e.Value)
+}
@@ -1,6 +1,7 @@
 package setting_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/jamestunnell/go-setting"
@@ -35,14 +36,57 @@ func TestElementWithIncompatibleConstraints(t *testing.T) {
 	lt := constraint.NewLess(value.NewFloat(5.0))
 	le := constraint.NewLessEqual(value.NewFloat(5.0))
 	e := setting.NewElement(startVal, lt, le)
+	err := e.CheckConstraints()
 
-	assert.Error(t, e.CheckConstraints())
+	assert.True(t, errors.Is(err, constraint.ErrIncompatibleConstraints))
 }
 
 func TestElementWithInapplicableConstraints(t *testing.T) {
 	startVal := value.NewFloat(1.0)
 	minlen := constraint.NewMinLen(5)
 	e := setting.NewElement(startVal, minlen)
+	err := e.CheckConstraints()
+
+	assert.True(t, errors.Is(err, constraint.ErrNotApplicable))
+}
+
+func TestElementValidate(t *testing.T) {
+	val := value.NewInt(50)
+	e := setting.NewElement(val, constraint.NewAnyOf(
+		constraint.NewLess(value.NewInt(10)),
+		constraint.NewGreater(value.NewInt(100))))
+
+	assert.NoError(t, e.CheckConstraints())
+	assert.Error(t, e.Validate())
+
+	val.Set(5)
+
+	assert.NoError(t, e.Validate())
+}
+
+func TestElementWithInapplicableWrappedConstraint(t *testing.T) {
+	e := setting.NewElement(value.NewInt(0),
+		constraint.NewNot(constraint.NewMinLen(2)))
 
 	assert.Error(t, e.CheckConstraints())
 }
+
+func TestElementWithUnsatisfiableConstraints(t *testing.T) {
+	e := setting.NewElement(value.NewInt(0),
+		constraint.NewMultipleOf(value.NewInt(4096)),
+		constraint.NewGreater(value.NewInt(0)),
+		constraint.NewLess(value.NewInt(4096)))
+
+	assert.Error(t, e.CheckConstraints())
+}
+
+func TestElementSensitive(t *testing.T) {
+	e := setting.NewElement(value.NewString("hunter2"))
+
+	assert.Equal(t, "hunter2", e.String())
+
+	e.Sensitive = true
+
+	assert.Equal(t, value.Redacted, e.String())
+	assert.Equal(t, "hunter2", e.RevealString())
+}
@@ -0,0 +1,168 @@
+package setting
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EnvName returns the environment variable name for an element path, with
+// an optional prefix. Names are upper-cased, and other characters than letters
+// and digits become underscores. For example, the path "upstreams[2]", "port"
+// with prefix "APP" gives "APP_UPSTREAMS_2_PORT".
+func EnvName(prefix string, path []string) string {
+	tokens := []string{}
+
+	if prefix != "" {
+		tokens = append(tokens, prefix)
+	}
+
+	for _, name := range path {
+		tokens = append(tokens, envToken(name))
+	}
+
+	return strings.Join(tokens, "_")
+}
+
+// LoadEnv sets element values from environment variables, given as
+// "KEY=value" strings such as from os.Environ. Variables are matched to
+// element paths as in EnvName, and variables that start with the prefix but do
+// not match an element are ignored. Indexed list items are added as needed,
+// as in LoadFlat, but map entries are only matched if they already exist.
+// Values are not validated.
+// Returns a non-nil error for a value that cannot be set.
+func (g *Group) LoadEnv(prefix string, environ []string) error {
+	values := map[string]string{}
+
+	for _, kv := range environ {
+		key, val := kv, ""
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key, val = kv[:i], kv[i+1:]
+		}
+
+		if prefix != "" {
+			if !strings.HasPrefix(key, prefix+"_") {
+				continue
+			}
+
+			key = key[len(prefix)+1:]
+		}
+
+		if path, found := g.envPath(key); found {
+			values[path] = val
+		}
+	}
+
+	return g.LoadFlat(values)
+}
+
+// envPath finds the dotted path of the element for an environment variable
+// name (without prefix). A name that matches the old path of an alias gives
+// the old path, which is resolved when loaded. Returns false if not found.
+func (g *Group) envPath(key string) (string, bool) {
+	if path, found := g.envNamePath(key); found {
+		return path, true
+	}
+
+	for _, alias := range g.Aliases {
+		if len(alias.Old) == 0 || len(alias.New) == 0 {
+			continue
+		}
+
+		oldToken := EnvName("", alias.Old)
+		if key != oldToken && !strings.HasPrefix(key, oldToken+"_") {
+			continue
+		}
+
+		path, found := g.envNamePath(EnvName("", alias.New) + key[len(oldToken):])
+		newPath := JoinPath(alias.New)
+
+		if !found || !strings.HasPrefix(path, newPath) {
+			continue
+		}
+
+		if rest := path[len(newPath):]; rest == "" || rest[0] == '.' || rest[0] == '[' {
+			return JoinPath(alias.Old) + rest, true
+		}
+	}
+
+	return "", false
+}
+
+// envNamePath finds the dotted path of the element for an environment variable
+// name by the names in the group, without aliases.
+func (g *Group) envNamePath(key string) (string, bool) {
+	for _, name := range g.Names() {
+		token := envToken(name)
+
+		if _, found := g.Elements[name]; found {
+			if key == token {
+				return JoinPath([]string{name}), true
+			}
+
+			continue
+		}
+
+		if !strings.HasPrefix(key, token+"_") {
+			continue
+		}
+
+		rest := key[len(token)+1:]
+
+		if subgroup, found := g.Subgroups[name]; found {
+			if path, found := subgroup.envPath(rest); found {
+				return JoinPath([]string{name}) + "." + path, true
+			}
+
+			continue
+		}
+
+		if m, found := g.Maps[name]; found {
+			for _, key := range m.Keys() {
+				keyToken := envToken(key)
+				if !strings.HasPrefix(rest, keyToken+"_") {
+					continue
+				}
+
+				if path, found := m.Entries[key].envPath(rest[len(keyToken)+1:]); found {
+					return JoinPath([]string{name, key}) + "." + path, true
+				}
+			}
+
+			continue
+		}
+
+		list, found := g.Lists[name]
+		if !found {
+			continue
+		}
+
+		end := strings.IndexByte(rest, '_')
+		if end < 0 {
+			continue
+		}
+
+		index, err := strconv.Atoi(rest[:end])
+		if err != nil || index < 0 {
+			continue
+		}
+
+		if path, found := list.Template.envPath(rest[end+1:]); found {
+			return JoinPath([]string{name}) + "[" + strconv.Itoa(index) + "]." + path, true
+		}
+	}
+
+	return "", false
+}
+
+func envToken(name string) string {
+	token := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+
+		return '_'
+	}, name)
+
+	return strings.Trim(token, "_")
+}
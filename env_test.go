@@ -0,0 +1,44 @@
+package setting_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "APP_UPSTREAMS_2_PORT", setting.EnvName("APP", []string{"upstreams[2]", "port"}))
+	assert.Equal(t, "DB_MAX_CONNS", setting.EnvName("", []string{"db", "max-conns"}))
+	assert.Equal(t, "A_B", setting.EnvName("", []string{"a.b"}))
+}
+
+func TestLoadEnv(t *testing.T) {
+	proxy := &testProxy{}
+	g := newTestProxyGroup(t, proxy)
+
+	err := g.LoadEnv("APP", []string{
+		"APP_NAME=p",
+		"APP_UPSTREAMS_1_PORT=8080",
+		"APP_UPSTREAMS_0_HOST=a=b",
+		"APP_UNKNOWN=1",
+		"APP_UPSTREAMS_X_PORT=1",
+		"HOME=/root",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &testProxy{Name: "p", Upstreams: []testUpstream{
+		{Host: "a=b"},
+		{Port: 8080},
+	}}, proxy)
+
+	assert.Error(t, g.LoadEnv("APP", []string{"APP_UPSTREAMS_0_PORT=x"}))
+}
+
+func TestLoadEnvNoPrefix(t *testing.T) {
+	app := &testApp{}
+	g, _ := setting.FromStruct(app)
+
+	assert.NoError(t, g.LoadEnv("", []string{"PORT=80", "DB_USER=u", "REGIONS=us,eu"}))
+	assert.Equal(t, &testApp{Port: 80, Regions: []string{"us", "eu"}, DB: testDB{User: "u"}}, app)
+}
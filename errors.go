@@ -0,0 +1,179 @@
+package setting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// CodeNotFound is the error code for a NotFoundError
+const CodeNotFound = "not_found"
+
+// ErrNotFound matches any NotFoundError using errors.Is
+var ErrNotFound = errors.New("setting not found")
+
+// NotFoundError indicates that there is no setting at a path.
+type NotFoundError struct {
+	Path []string
+	// Index is the slice index that was out of range, if any
+	Index int
+}
+
+// Error returns the error message.
+func (e *NotFoundError) Error() string {
+	if e.Index >= 0 {
+		return fmt.Sprintf("%s: index %d is out of range", strings.Join(e.Path, "."), e.Index)
+	}
+
+	return fmt.Sprintf("%s: not found", strings.Join(e.Path, "."))
+}
+
+// Code returns CodeNotFound.
+func (e *NotFoundError) Code() string { return CodeNotFound }
+
+// Is returns true for ErrNotFound.
+func (e *NotFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// CodeItemCount is the error code for an ItemCountError
+const CodeItemCount = "item_count"
+
+// ErrItemCount matches any ItemCountError using errors.Is
+var ErrItemCount = errors.New("wrong number of list items")
+
+// ItemCountError indicates that a list has too few or too many items.
+type ItemCountError struct {
+	Path     []string
+	Count    int
+	MinItems int
+	// MaxItems is zero if there is no maximum
+	MaxItems int
+}
+
+// Error returns the error message.
+func (e *ItemCountError) Error() string {
+	return fmt.Sprintf("%s: has %d items, expected %s",
+		strings.Join(e.Path, "."), e.Count, e.Expected())
+}
+
+// Expected describes the expected number of items, such as "at least 1".
+func (e *ItemCountError) Expected() string {
+	if e.Count < e.MinItems {
+		return fmt.Sprintf("at least %d", e.MinItems)
+	}
+
+	return fmt.Sprintf("at most %d", e.MaxItems)
+}
+
+// Code returns CodeItemCount.
+func (e *ItemCountError) Code() string { return CodeItemCount }
+
+// Is returns true for ErrItemCount.
+func (e *ItemCountError) Is(target error) bool { return target == ErrItemCount }
+
+// CodeUnsupportedSchema is the error code for an UnsupportedSchemaError
+const CodeUnsupportedSchema = "unsupported_schema"
+
+// ErrUnsupportedSchema matches any UnsupportedSchemaError using errors.Is
+var ErrUnsupportedSchema = errors.New("unsupported schema keywords")
+
+// UnsupportedSchemaError indicates that a schema has keywords that cannot be
+// imported (see FromJSONSchema).
+type UnsupportedSchemaError struct {
+	// Keywords are JSON Pointers to the unsupported keywords, such as
+	// "/properties/port/format", in sorted order
+	Keywords []string
+}
+
+// Error returns the error message.
+func (e *UnsupportedSchemaError) Error() string {
+	return "unsupported JSON Schema keywords: " + strings.Join(e.Keywords, ", ")
+}
+
+// Code returns CodeUnsupportedSchema.
+func (e *UnsupportedSchemaError) Code() string { return CodeUnsupportedSchema }
+
+// Is returns true for ErrUnsupportedSchema.
+func (e *UnsupportedSchemaError) Is(target error) bool { return target == ErrUnsupportedSchema }
+
+// ErrorCode returns the machine-readable code of the given error, such as
+// constraint.CodeViolation or value.CodeTypeMismatch.
+// Returns an empty string if the error does not have a code.
+func ErrorCode(err error) string {
+	var coded interface{ Code() string }
+
+	if errors.As(err, &coded) {
+		return coded.Code()
+	}
+
+	return ""
+}
+
+// ErrorPath returns the setting path of the given error (or the first
+// structured error that it wraps).
+// Returns nil if the error does not have a path.
+func ErrorPath(err error) []string {
+	if pathPtr := errorPathPtr(err); pathPtr != nil {
+		return *pathPtr
+	}
+
+	return nil
+}
+
+// annotateError sets the path of a structured error, and redacts values
+// in the error chain if the element is sensitive. Returns the same error.
+func annotateError(err error, path []string, sensitive bool) error {
+	if pathPtr := errorPathPtr(err); pathPtr != nil {
+		*pathPtr = path
+	}
+
+	if sensitive {
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			switch e := e.(type) {
+			case *value.ParseError:
+				e.Redacted = true
+			case *constraint.ViolationError:
+				e.Redacted = true
+			case *constraint.NotApplicableError:
+				e.Redacted = true
+			}
+		}
+	}
+
+	return err
+}
+
+// errorPathPtr finds the path field of the outermost structured error.
+func errorPathPtr(err error) *[]string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case *value.TypeMismatchError:
+			return &e.Path
+		case *value.ParseError:
+			return &e.Path
+		case *constraint.ViolationError:
+			return &e.Path
+		case *constraint.NotApplicableError:
+			return &e.Path
+		case *constraint.IncompatibleConstraintsError:
+			return &e.Path
+		case *NotFoundError:
+			return &e.Path
+		case *ItemCountError:
+			return &e.Path
+		}
+	}
+
+	return nil
+}
+
+// appendPath makes a new path, so that the given path is not modified.
+func appendPath(path []string, names ...string) []string {
+	newPath := make([]string, len(path), len(path)+len(names))
+
+	copy(newPath, path)
+
+	return append(newPath, names...)
+}
@@ -0,0 +1,87 @@
+package setting_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCodeAndPath(t *testing.T) {
+	g := &setting.Group{
+		Elements:  map[string]*setting.Element{},
+		Subgroups: map[string]*setting.Group{"X": newTestGroup()},
+	}
+
+	g.FindElement("X", "A").Value.(*value.Float).Set(11.0)
+
+	err := g.Validate()
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, constraint.CodeViolation, setting.ErrorCode(err))
+	assert.Equal(t, []string{"X", "A"}, setting.ErrorPath(err))
+	assert.Equal(t, "X.A: value 11 does not satisfy x < 10", err.Error())
+}
+
+func TestErrorCodeAndPathNotStructured(t *testing.T) {
+	err := errors.New("plain")
+
+	assert.Empty(t, setting.ErrorCode(err))
+	assert.Nil(t, setting.ErrorPath(err))
+}
+
+func TestGroupCheckConstraintsErrorPath(t *testing.T) {
+	g := newTestGroup()
+	g.Elements["C"] = setting.NewElement(value.NewInt(0), constraint.NewUnique())
+
+	err := g.CheckConstraints()
+
+	assert.True(t, errors.Is(err, constraint.ErrNotApplicable))
+	assert.Equal(t, constraint.CodeNotApplicable, setting.ErrorCode(err))
+	assert.Equal(t, []string{"C"}, setting.ErrorPath(err))
+}
+
+func TestErrorsRedactedForSensitiveElement(t *testing.T) {
+	oneOf := constraint.NewOneOf(value.NewStringSlice("x"))
+	each := constraint.NewEach(oneOf)
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"token":  {Value: value.NewString("abc"), Sensitive: true},
+			"tokens": {Value: value.NewStringSlice("abc"), Sensitive: true},
+		},
+		Subgroups: map[string]*setting.Group{},
+	}
+
+	g.Elements["token"].Constraints = []constraint.Constraint{oneOf}
+
+	err := g.Validate()
+
+	assert.Equal(t, "token: value ****** does not satisfy x in [x]", err.Error())
+	assert.Equal(t, "token must be one of [x]", setting.NewCatalog().Render(err, "en"))
+
+	g.Elements["token"].Constraints = nil
+	g.Elements["tokens"].Constraints = []constraint.Constraint{each}
+	err = g.Validate()
+
+	assert.NotContains(t, err.Error(), "abc")
+
+	g.Elements["num"] = &setting.Element{Value: value.NewInt(0), Sensitive: true}
+	err = g.Load(map[string]interface{}{"num": "abc"})
+
+	assert.NotContains(t, err.Error(), "abc")
+	assert.NotContains(t, setting.NewCatalog().Render(err, "en"), "abc")
+
+	// the reason can include values
+	g.Elements["num"] = &setting.Element{
+		Value:       value.NewIntSlice(40, 2),
+		Constraints: []constraint.Constraint{constraint.NewSumLessEqual(value.NewInt(10))},
+		Sensitive:   true,
+	}
+	err = g.Validate()
+
+	assert.NotContains(t, err.Error(), "42")
+	assert.NotContains(t, setting.NewCatalog().Render(err, "en"), "42")
+}
@@ -0,0 +1,950 @@
+package setting
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// schemaAnnotations are keywords without effect on validation, which are
+// accepted in any schema.
+var schemaAnnotations = []string{"$schema", "$id", "$comment", "title", "description", "readOnly"}
+
+// valueTypes are the value types for JSON Schema types, other than arrays and objects.
+var valueTypes = map[string]value.Type{
+	"integer": value.TypeInt,
+	"number":  value.TypeFloat,
+	"boolean": value.TypeBool,
+	"string":  value.TypeString,
+}
+
+// ReadJSONSchema decodes a JSON Schema document from the reader and makes a
+// group using FromJSONSchema.
+func ReadJSONSchema(r io.Reader) (*Group, error) {
+	schema, err := decodeJSON(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromJSONSchema(schema)
+}
+
+// FromJSONSchema makes a group from a JSON Schema document, such as one decoded
+// by encoding/json, so that it can be used for validation. The schema must be
+// an object type. Properties with object types become subgroups (which are
+// optional if they are nullable), properties with arrays of objects become
+// lists, properties with objects of additional properties become maps, and
+// other properties become elements with constraints. The supported keywords
+// are type, properties, required, additionalProperties, propertyNames, items,
+// enum, const (in contains), minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, multipleOf, minLength, maxLength, pattern, minItems,
+// maxItems, uniqueItems, contains, allOf, anyOf, not, default, examples,
+// deprecated and writeOnly, as well as annotations such as title and
+// description. This includes every schema made by Group.JSONSchema, where a
+// file property of a sensitive element (see FileSuffix) is not made an
+// element, and an alias becomes a deprecated element. Some information is not
+// kept by such a round trip: an unsigned integer element becomes an integer
+// element with a GreaterEqual(0) constraint, and minLength and maxLength
+// become MinLen and MaxLen, which count bytes rather than characters.
+// Returns an *UnsupportedSchemaError listing any other keywords, or a
+// non-nil error if the schema is not valid.
+func FromJSONSchema(schema map[string]interface{}) (*Group, error) {
+	unsupported := []string{}
+
+	typeName, _, err := schemaType(schema, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if typeName != "object" {
+		return nil, fmt.Errorf("expected object schema, got type %q", typeName)
+	}
+
+	g, err := groupFromSchema(schema, "", &unsupported)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+
+		return nil, &UnsupportedSchemaError{Keywords: unsupported}
+	}
+
+	return g, nil
+}
+
+func groupFromSchema(schema map[string]interface{}, pointer string, unsupported *[]string) (*Group, error) {
+	checkKeywords(schema, pointer, unsupported, "type", "properties", "required", "additionalProperties", "allOf")
+
+	if raw, found := schema["additionalProperties"]; found {
+		if _, ok := raw.(bool); !ok {
+			*unsupported = append(*unsupported, pointer+"/additionalProperties")
+		}
+	}
+
+	g := &Group{
+		Elements:  map[string]*Element{},
+		Subgroups: map[string]*Group{},
+		Lists:     map[string]*List{},
+		Maps:      map[string]*Map{},
+	}
+
+	properties, err := schemaObject(schema, "properties", pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, raw := range properties {
+		if isFileProperty(properties, name) {
+			continue
+		}
+
+		propPointer := pointer + "/properties/" + pointerToken(name)
+
+		propSchema, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected schema object, got %T", propPointer, raw)
+		}
+
+		if err = g.addFromSchema(name, propSchema, propPointer, unsupported); err != nil {
+			return nil, err
+		}
+	}
+
+	required, err := schemaStrings(schema, "required", pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range required {
+		elem, found := g.Elements[name]
+		if !found {
+			if _, found := properties[name]; !found {
+				return nil, fmt.Errorf("%s/required: property %q is not defined", pointer, name)
+			}
+
+			continue
+		}
+
+		elem.Required = true
+	}
+
+	if err = g.requiredFromSchema(schema, pointer, unsupported); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// isFileProperty returns true for the file property of a sensitive element,
+// as made by Group.JSONSchema.
+func isFileProperty(properties map[string]interface{}, name string) bool {
+	if !strings.HasSuffix(name, FileSuffix) {
+		return false
+	}
+
+	elemSchema, ok := properties[strings.TrimSuffix(name, FileSuffix)].(map[string]interface{})
+	if !ok || elemSchema["writeOnly"] != true {
+		return false
+	}
+
+	fileSchema, ok := properties[name].(map[string]interface{})
+
+	return ok && len(fileSchema) == 1 && fileSchema["type"] == "string"
+}
+
+// requiredFromSchema marks sensitive elements as required if allOf requires
+// either the element or its file property, as made by Group.JSONSchema. Other
+// allOf schemas are not supported.
+func (g *Group) requiredFromSchema(schema map[string]interface{}, pointer string, unsupported *[]string) error {
+	raw, found := schema["allOf"]
+	if !found {
+		return nil
+	}
+
+	subschemas, ok := raw.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s/allOf: expected array, got %T", pointer, raw)
+	}
+
+	for i, subschema := range subschemas {
+		if name, ok := requiredEither(subschema); ok && g.Elements[name] != nil {
+			g.Elements[name].Required = true
+
+			continue
+		}
+
+		*unsupported = append(*unsupported, fmt.Sprintf("%s/allOf/%d", pointer, i))
+	}
+
+	return nil
+}
+
+// requiredEither returns the element name for a schema of the form
+// {"anyOf": [{"required": [name]}, {"required": [name + FileSuffix]}]}.
+func requiredEither(raw interface{}) (string, bool) {
+	schema, ok := raw.(map[string]interface{})
+	if !ok || len(schema) != 1 {
+		return "", false
+	}
+
+	anyOf, ok := schema["anyOf"].([]interface{})
+	if !ok || len(anyOf) != 2 {
+		return "", false
+	}
+
+	names := make([]string, len(anyOf))
+
+	for i, raw := range anyOf {
+		required, ok := raw.(map[string]interface{})
+		if !ok || len(required) != 1 {
+			return "", false
+		}
+
+		strs, err := schemaStrings(required, "required", "")
+		if err != nil || len(strs) != 1 {
+			return "", false
+		}
+
+		names[i] = strs[0]
+	}
+
+	return names[0], names[1] == names[0]+FileSuffix
+}
+
+// addFromSchema adds a subgroup, list, map or element made from a property schema.
+func (g *Group) addFromSchema(
+	name string,
+	schema map[string]interface{},
+	pointer string,
+	unsupported *[]string,
+) error {
+	typeName, nullable, err := schemaType(schema, pointer)
+	if err != nil {
+		return err
+	}
+
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok && typeName == "object" {
+		if nullable {
+			*unsupported = append(*unsupported, pointer+"/type")
+		}
+
+		m, err := mapFromSchema(schema, additional, pointer, unsupported)
+		if err != nil {
+			return err
+		}
+
+		g.Maps[name] = m
+
+		return nil
+	}
+
+	if typeName == "object" {
+		subgroup, err := groupFromSchema(schema, pointer, unsupported)
+		if err != nil {
+			return err
+		}
+
+		subgroup.Optional = nullable
+		g.Subgroups[name] = subgroup
+
+		return nil
+	}
+
+	if nullable {
+		*unsupported = append(*unsupported, pointer+"/type")
+	}
+
+	items, err := schemaObject(schema, "items", pointer)
+	if err != nil {
+		return err
+	}
+
+	itemsType, _, err := schemaType(items, pointer+"/items")
+	if err != nil {
+		return err
+	}
+
+	if typeName == "array" && itemsType == "object" {
+		list, err := listFromSchema(schema, items, pointer, unsupported)
+		if err != nil {
+			return err
+		}
+
+		g.Lists[name] = list
+
+		return nil
+	}
+
+	elem, err := elementFromSchema(schema, typeName, pointer, unsupported)
+	if err != nil {
+		return err
+	}
+
+	g.Elements[name] = elem
+
+	return nil
+}
+
+func listFromSchema(
+	schema, items map[string]interface{},
+	pointer string,
+	unsupported *[]string,
+) (*List, error) {
+	checkKeywords(schema, pointer, unsupported, "type", "items", "minItems", "maxItems")
+
+	template, err := groupFromSchema(items, pointer+"/items", unsupported)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+
+	for _, keyword := range []string{"minItems", "maxItems"} {
+		if raw, found := schema[keyword]; found {
+			n, err := schemaLen(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s/%s: %w", pointer, keyword, err)
+			}
+
+			counts[keyword] = int(n)
+		}
+	}
+
+	return NewList(template, counts["minItems"], counts["maxItems"]), nil
+}
+
+// mapFromSchema makes a map from an object schema with additional properties,
+// which must be object schemas. Property names give the key constraints.
+func mapFromSchema(
+	schema, additional map[string]interface{},
+	pointer string,
+	unsupported *[]string,
+) (*Map, error) {
+	checkKeywords(schema, pointer, unsupported, "type", "additionalProperties", "propertyNames")
+
+	additionalPointer := pointer + "/additionalProperties"
+
+	typeName, _, err := schemaType(additional, additionalPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if typeName != "object" {
+		*unsupported = append(*unsupported, additionalPointer)
+
+		return NewMap(&Group{}), nil
+	}
+
+	template, err := groupFromSchema(additional, additionalPointer, unsupported)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := schemaObject(schema, "propertyNames", pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	namesPointer := pointer + "/propertyNames"
+
+	checkKeywords(names, namesPointer, unsupported, constraintKeywords(value.TypeString, false)...)
+
+	keyConstraints, err := fragmentConstraints(names, value.TypeString, false, namesPointer, unsupported)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMap(template, keyConstraints...), nil
+}
+
+func elementFromSchema(
+	schema map[string]interface{},
+	typeName string,
+	pointer string,
+	unsupported *[]string,
+) (*Element, error) {
+	var (
+		val         value.Value
+		constraints []constraint.Constraint
+		err         error
+	)
+
+	if typeName == "array" {
+		checkKeywords(schema, pointer, unsupported, append(constraintKeywords(value.TypeString, true),
+			"type", "default", "examples", "deprecated", "writeOnly")...)
+
+		val, constraints, err = sliceFromSchema(schema, pointer, unsupported)
+	} else {
+		val, constraints, err = singleFromSchema(schema, typeName, pointer, unsupported)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	elem := NewElement(val, constraints...)
+
+	if err = elem.CheckConstraints(); err != nil {
+		return nil, fmt.Errorf("%s: %w", pointer, err)
+	}
+
+	if raw, found := schema["default"]; found {
+		if err = setRaw(val, raw); err != nil {
+			return nil, fmt.Errorf("%s/default: %w", pointer, err)
+		}
+	}
+
+	if elem.Metadata.Description, err = schemaString(schema, "description", pointer); err != nil {
+		return nil, err
+	}
+
+	if elem.Sensitive, err = schemaBool(schema, "writeOnly", pointer); err != nil {
+		return nil, err
+	}
+
+	if elem.Metadata.Examples, err = schemaExamples(schema, pointer); err != nil {
+		return nil, err
+	}
+
+	deprecated, err := schemaBool(schema, "deprecated", pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if deprecated {
+		elem.Deprecated = &Deprecation{}
+	}
+
+	return elem, nil
+}
+
+// sliceFromSchema makes a slice value and constraints from an array schema.
+// Constraints given by the items schema apply to each slice element.
+func sliceFromSchema(
+	schema map[string]interface{},
+	pointer string,
+	unsupported *[]string,
+) (value.Value, []constraint.Constraint, error) {
+	items, err := schemaObject(schema, "items", pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if items == nil {
+		return nil, nil, fmt.Errorf("%s: array schema has no items", pointer)
+	}
+
+	itemsPointer := pointer + "/items"
+
+	typeName, nullable, err := schemaType(items, itemsPointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if nullable {
+		*unsupported = append(*unsupported, itemsPointer+"/type")
+	}
+
+	// these are only supported for the array itself
+	for _, keyword := range []string{"default", "examples", "deprecated", "writeOnly"} {
+		if _, found := items[keyword]; found {
+			*unsupported = append(*unsupported, itemsPointer+"/"+keyword)
+		}
+	}
+
+	single, itemConstraints, err := singleFromSchema(items, typeName, itemsPointer, unsupported)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// items were handled above, since they give the value type
+	rest := map[string]interface{}{}
+
+	for keyword, raw := range schema {
+		if keyword != "items" {
+			rest[keyword] = raw
+		}
+	}
+
+	constraints, err := fragmentConstraints(rest, single.Type(), true, pointer, unsupported)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(itemConstraints) > 0 {
+		constraints = append(constraints, constraint.NewEach(itemConstraints...))
+	}
+
+	return value.NewSlice(single.Type()), constraints, nil
+}
+
+// singleFromSchema makes a single value and constraints from a schema with
+// an integer, number, boolean or string type.
+func singleFromSchema(
+	schema map[string]interface{},
+	typeName string,
+	pointer string,
+	unsupported *[]string,
+) (value.Value, []constraint.Constraint, error) {
+	if typeName == "" {
+		return nil, nil, fmt.Errorf("%s: schema has no type", pointer)
+	}
+
+	t, found := valueTypes[typeName]
+	if !found {
+		return nil, nil, fmt.Errorf("%s/type: unsupported type %q", pointer, typeName)
+	}
+
+	checkKeywords(schema, pointer, unsupported, append(constraintKeywords(t, false),
+		"type", "default", "examples", "deprecated", "writeOnly")...)
+
+	constraints, err := fragmentConstraints(schema, t, false, pointer, unsupported)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return value.NewSingle(t), constraints, nil
+}
+
+// constraintKeywords returns the keywords that make constraints for a single
+// or slice value type, in the order that constraints are made.
+func constraintKeywords(t value.Type, slice bool) []string {
+	if slice {
+		return []string{"minItems", "maxItems", "uniqueItems", "contains", "not", "allOf", "anyOf", "items"}
+	}
+
+	keywords := []string{"enum"}
+
+	switch t {
+	case value.TypeInt, value.TypeFloat:
+		keywords = append(keywords, "minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum", "multipleOf")
+	case value.TypeString:
+		keywords = append(keywords, "minLength", "maxLength", "pattern")
+	}
+
+	return append(keywords, "not", "allOf", "anyOf")
+}
+
+// fragmentConstraints makes constraints from the keywords of a schema, or of
+// a schema fragment such as in allOf, for a single or slice value type.
+// Keywords are handled in a fixed order, so that constraints are too.
+func fragmentConstraints(
+	schema map[string]interface{},
+	t value.Type,
+	slice bool,
+	pointer string,
+	unsupported *[]string,
+) ([]constraint.Constraint, error) {
+	constraints := []constraint.Constraint{}
+
+	for _, keyword := range constraintKeywords(t, slice) {
+		raw, found := schema[keyword]
+		if !found {
+			continue
+		}
+
+		keywordPointer := pointer + "/" + keyword
+
+		var (
+			c   constraint.Constraint
+			err error
+		)
+
+		switch keyword {
+		case "not", "allOf", "anyOf":
+			c, err = compositeFromSchema(keyword, raw, t, slice, keywordPointer, unsupported)
+		case "items":
+			c, err = eachFromSchema(raw, t, keywordPointer, unsupported)
+		case "contains":
+			c, err = containsFromSchema(raw, t, keywordPointer, unsupported)
+		default:
+			if c, err = constraintFromSchema(keyword, raw, t); err != nil {
+				err = fmt.Errorf("%s: %w", keywordPointer, err)
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if c != nil {
+			constraints = append(constraints, c)
+		}
+	}
+
+	return constraints, nil
+}
+
+// compositeFromSchema makes a constraint from not, allOf or anyOf. A schema
+// fragment with more than one constraint gives an AllOf, and not with only
+// enum gives a NoneOf.
+func compositeFromSchema(
+	keyword string,
+	raw interface{},
+	t value.Type,
+	slice bool,
+	pointer string,
+	unsupported *[]string,
+) (constraint.Constraint, error) {
+	fragment := func(raw interface{}, pointer string) (constraint.Constraint, error) {
+		schema, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected schema object, got %T", pointer, raw)
+		}
+
+		checkKeywords(schema, pointer, unsupported, constraintKeywords(t, slice)...)
+
+		constraints, err := fragmentConstraints(schema, t, slice, pointer, unsupported)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(constraints) == 1 {
+			return constraints[0], nil
+		}
+
+		return constraint.NewAllOf(constraints...), nil
+	}
+
+	if keyword == "not" {
+		if schema, ok := raw.(map[string]interface{}); ok && len(schema) == 1 && !slice {
+			if enum, found := schema["enum"]; found {
+				c, err := constraintFromSchema("enum", enum, t)
+				if err != nil {
+					return nil, fmt.Errorf("%s/enum: %w", pointer, err)
+				}
+
+				return constraint.NewNoneOf(c.Param().(value.Slice)), nil
+			}
+		}
+
+		c, err := fragment(raw, pointer)
+		if err != nil {
+			return nil, err
+		}
+
+		return constraint.NewNot(c), nil
+	}
+
+	subschemas, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected array, got %T", pointer, raw)
+	}
+
+	constraints := make([]constraint.Constraint, len(subschemas))
+
+	for i, subschema := range subschemas {
+		c, err := fragment(subschema, fmt.Sprintf("%s/%d", pointer, i))
+		if err != nil {
+			return nil, err
+		}
+
+		constraints[i] = c
+	}
+
+	if keyword == "allOf" {
+		return constraint.NewAllOf(constraints...), nil
+	}
+
+	return constraint.NewAnyOf(constraints...), nil
+}
+
+// eachFromSchema makes an Each constraint from an items schema fragment.
+// Returns nil if the fragment has no constraints.
+func eachFromSchema(raw interface{}, t value.Type, pointer string, unsupported *[]string) (constraint.Constraint, error) {
+	schema, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected schema object, got %T", pointer, raw)
+	}
+
+	checkKeywords(schema, pointer, unsupported, constraintKeywords(t, false)...)
+
+	constraints, err := fragmentConstraints(schema, t, false, pointer, unsupported)
+	if err != nil || len(constraints) == 0 {
+		return nil, err
+	}
+
+	return constraint.NewEach(constraints...), nil
+}
+
+// containsFromSchema makes a Contains constraint from a contains schema with
+// only const. Other contains schemas are not supported.
+func containsFromSchema(
+	raw interface{},
+	t value.Type,
+	pointer string,
+	unsupported *[]string,
+) (constraint.Constraint, error) {
+	schema, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected schema object, got %T", pointer, raw)
+	}
+
+	if _, found := schema["const"]; !found || len(schema) != 1 {
+		*unsupported = append(*unsupported, pointer)
+
+		return nil, nil
+	}
+
+	single := value.NewSingle(t)
+	if err := setRaw(single, schema["const"]); err != nil {
+		return nil, fmt.Errorf("%s/const: %w", pointer, err)
+	}
+
+	return constraint.NewContains(single), nil
+}
+
+// constraintFromSchema makes a constraint from a keyword for a value type.
+// Returns nil for keywords that do not make constraints.
+func constraintFromSchema(keyword string, raw interface{}, t value.Type) (constraint.Constraint, error) {
+	switch keyword {
+	case "enum":
+		if _, ok := raw.([]interface{}); !ok {
+			return nil, fmt.Errorf("expected array, got %T", raw)
+		}
+
+		slice := value.NewSlice(t)
+		if err := setRaw(slice, raw); err != nil {
+			return nil, err
+		}
+
+		return constraint.NewOneOf(slice), nil
+	case "minLength", "maxLength", "minItems", "maxItems":
+		n, err := schemaLen(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if keyword == "minLength" || keyword == "minItems" {
+			return constraint.NewMinLen(n), nil
+		}
+
+		return constraint.NewMaxLen(n), nil
+	case "uniqueItems":
+		unique, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected boolean, got %T", raw)
+		}
+
+		if unique {
+			return constraint.NewUnique(), nil
+		}
+
+		return nil, nil
+	case "pattern":
+		str, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", raw)
+		}
+
+		re, err := regexp.Compile(str)
+		if err != nil {
+			return nil, err
+		}
+
+		return constraint.NewPattern(re), nil
+	case "minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum", "multipleOf":
+		single := value.NewSingle(t)
+		if err := setRaw(single, raw); err != nil {
+			return nil, err
+		}
+
+		switch keyword {
+		case "minimum":
+			return constraint.NewGreaterEqual(single), nil
+		case "exclusiveMinimum":
+			return constraint.NewGreater(single), nil
+		case "maximum":
+			return constraint.NewLessEqual(single), nil
+		case "exclusiveMaximum":
+			return constraint.NewLess(single), nil
+		}
+
+		return constraint.NewMultipleOf(single), nil
+	}
+
+	return nil, nil
+}
+
+// checkKeywords adds any keywords of the schema that are not allowed (or
+// annotations) to the unsupported keyword pointers.
+func checkKeywords(schema map[string]interface{}, pointer string, unsupported *[]string, allowed ...string) {
+	for keyword := range schema {
+		if !containsString(allowed, keyword) && !containsString(schemaAnnotations, keyword) {
+			*unsupported = append(*unsupported, pointer+"/"+pointerToken(keyword))
+		}
+	}
+}
+
+// schemaType returns the type of a schema, and whether it is nullable (the type is
+// an array with "null"). The type is "object" if not given but there are properties.
+// Returns an empty type if not given.
+func schemaType(schema map[string]interface{}, pointer string) (string, bool, error) {
+	switch raw := schema["type"].(type) {
+	case nil:
+		if _, found := schema["properties"]; found {
+			return "object", false, nil
+		}
+
+		return "", false, nil
+	case string:
+		return raw, false, nil
+	case []interface{}:
+		if len(raw) == 2 {
+			for i, other := range []int{1, 0} {
+				if raw[i] == "null" {
+					if typeName, ok := raw[other].(string); ok {
+						return typeName, true, nil
+					}
+				}
+			}
+		}
+	}
+
+	return "", false, fmt.Errorf("%s/type: unsupported type %v", pointer, schema["type"])
+}
+
+func schemaObject(schema map[string]interface{}, keyword, pointer string) (map[string]interface{}, error) {
+	raw, found := schema[keyword]
+	if !found {
+		return nil, nil
+	}
+
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: expected object, got %T", pointer, keyword, raw)
+	}
+
+	return obj, nil
+}
+
+func schemaStrings(schema map[string]interface{}, keyword, pointer string) ([]string, error) {
+	raw, found := schema[keyword]
+	if !found {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s/%s: expected array, got %T", pointer, keyword, raw)
+	}
+
+	strs := make([]string, len(items))
+
+	for i, item := range items {
+		if strs[i], ok = item.(string); !ok {
+			return nil, fmt.Errorf("%s/%s/%d: expected string, got %T", pointer, keyword, i, item)
+		}
+	}
+
+	return strs, nil
+}
+
+func schemaString(schema map[string]interface{}, keyword, pointer string) (string, error) {
+	raw, found := schema[keyword]
+	if !found {
+		return "", nil
+	}
+
+	str, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("%s/%s: expected string, got %T", pointer, keyword, raw)
+	}
+
+	return str, nil
+}
+
+func schemaBool(schema map[string]interface{}, keyword, pointer string) (bool, error) {
+	raw, found := schema[keyword]
+	if !found {
+		return false, nil
+	}
+
+	b, ok := raw.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s/%s: expected boolean, got %T", pointer, keyword, raw)
+	}
+
+	return b, nil
+}
+
+// schemaExamples returns examples as text, in the form accepted by Value.Parse.
+func schemaExamples(schema map[string]interface{}, pointer string) ([]string, error) {
+	raw, found := schema["examples"]
+	if !found {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s/examples: expected array, got %T", pointer, raw)
+	}
+
+	examples := make([]string, len(items))
+
+	for i, item := range items {
+		str, err := rawExample(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s/examples/%d: %w", pointer, i, err)
+		}
+
+		examples[i] = str
+	}
+
+	return examples, nil
+}
+
+// rawExample converts an example to a string. An array example, such as for
+// a slice element, becomes comma-separated values as parsed by Value.Parse.
+func rawExample(raw interface{}) (string, error) {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return rawString(raw)
+	}
+
+	strs := make([]string, len(items))
+
+	for i, item := range items {
+		str, err := rawString(item)
+		if err != nil {
+			return "", err
+		}
+
+		strs[i] = str
+	}
+
+	return strings.Join(strs, ", "), nil
+}
+
+// schemaLen parses a non-negative integer, such as for minLength.
+func schemaLen(raw interface{}) (uint64, error) {
+	str, err := rawString(raw)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(str, 10, 64)
+}
+
+// pointerToken escapes a name for use in a JSON Pointer.
+func pointerToken(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
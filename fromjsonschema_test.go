@@ -0,0 +1,330 @@
+package setting_test
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJSONSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Server",
+	"type": "object",
+	"additionalProperties": false,
+	"required": ["port", "tls"],
+	"properties": {
+		"port": {
+			"type": "integer", "minimum": 1, "exclusiveMaximum": 65536, "default": 8080,
+			"description": "Port to listen on", "examples": [80, 443]
+		},
+		"level": {"type": "string", "enum": ["debug", "info"], "default": "info"},
+		"name": {"type": "string", "minLength": 1, "maxLength": 16, "pattern": "^[a-z]+$"},
+		"ratio": {"type": "number", "exclusiveMinimum": 0, "maximum": 1, "multipleOf": 0.25, "default": 0.5},
+		"token": {"type": "string", "writeOnly": true, "deprecated": true},
+		"regions": {
+			"type": "array", "minItems": 1, "maxItems": 3, "uniqueItems": true,
+			"items": {"type": "string", "enum": ["eu", "us"]}, "default": ["eu"]
+		},
+		"tls": {
+			"type": "object",
+			"properties": {"enabled": {"type": "boolean"}}
+		},
+		"proxy": {
+			"type": ["object", "null"],
+			"properties": {"host": {"type": "string"}}
+		},
+		"upstreams": {
+			"type": "array", "minItems": 1,
+			"items": {"type": "object", "required": ["host"], "properties": {"host": {"type": "string"}}}
+		}
+	}
+}`
+
+func TestReadJSONSchema(t *testing.T) {
+	g, err := setting.ReadJSONSchema(strings.NewReader(testJSONSchema))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	port := g.Elements["port"]
+
+	assert.Equal(t, value.NewInt(8080), port.Value)
+	assert.True(t, port.Required)
+	assert.Equal(t, "Port to listen on", port.Metadata.Description)
+	assert.Equal(t, []string{"80", "443"}, port.Metadata.Examples)
+	assert.Equal(t, []constraint.Constraint{
+		constraint.NewGreaterEqual(value.NewInt(1)),
+		constraint.NewLess(value.NewInt(65536)),
+	}, port.Constraints)
+
+	level := g.Elements["level"]
+
+	assert.Equal(t, value.NewString("info"), level.Value)
+	assert.False(t, level.Required)
+	assert.Equal(t, []constraint.Constraint{
+		constraint.NewOneOf(value.NewStringSlice("debug", "info")),
+	}, level.Constraints)
+
+	name := g.Elements["name"]
+
+	assert.Len(t, name.Constraints, 3)
+	assert.Equal(t, "x matches ^[a-z]+$", name.Constraints[2].String())
+	assert.NoError(t, g.Set("name", "abc"))
+	assert.Error(t, g.Set("name", "ABC"))
+
+	assert.Equal(t, []constraint.Constraint{
+		constraint.NewGreater(value.NewFloat(0)),
+		constraint.NewLessEqual(value.NewFloat(1)),
+		constraint.NewMultipleOf(value.NewFloat(0.25)),
+	}, g.Elements["ratio"].Constraints)
+
+	assert.True(t, g.Elements["token"].Sensitive)
+	assert.NotNil(t, g.Elements["token"].Deprecated)
+
+	regions := g.Elements["regions"]
+
+	assert.Equal(t, value.NewStringSlice("eu"), regions.Value)
+	assert.Equal(t, []constraint.Constraint{
+		constraint.NewMinLen(1),
+		constraint.NewMaxLen(3),
+		constraint.NewUnique(),
+		constraint.NewEach(constraint.NewOneOf(value.NewStringSlice("eu", "us"))),
+	}, regions.Constraints)
+
+	assert.Equal(t, value.NewBool(false), g.Subgroups["tls"].Elements["enabled"].Value)
+	assert.False(t, g.Subgroups["tls"].Optional)
+	assert.True(t, g.Subgroups["proxy"].Optional)
+	assert.False(t, g.Subgroups["proxy"].IsPresent())
+
+	upstreams := g.Lists["upstreams"]
+
+	assert.Equal(t, 1, upstreams.MinItems)
+	assert.Equal(t, 0, upstreams.MaxItems)
+	assert.True(t, upstreams.Template.Elements["host"].Required)
+
+	assert.NoError(t, g.LoadJSON(strings.NewReader(`{"regions": ["eu", "fr"], "upstreams": [{"host": "a"}]}`)))
+
+	err = g.Validate()
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, []string{"regions"}, setting.ErrorPath(err))
+}
+
+func TestFromJSONSchemaRoundTrip(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"port": setting.NewElement(value.NewInt(8080),
+				constraint.NewGreater(value.NewInt(0)),
+				constraint.NewLessEqual(value.NewInt(65535)),
+			),
+			"hosts": setting.NewElement(value.NewStringSlice("a", "b"),
+				constraint.NewMaxLen(4),
+				constraint.NewEach(constraint.NewMinLen(1)),
+			),
+		},
+		Subgroups: map[string]*setting.Group{
+			"log": {
+				Elements: map[string]*setting.Element{
+					"level": setting.NewElement(value.NewString("info"),
+						constraint.NewOneOf(value.NewStringSlice("debug", "info"))),
+				},
+			},
+		},
+	}
+	g.Elements["port"].Required = true
+
+	schema, warnings := g.JSONSchema()
+
+	assert.Empty(t, warnings)
+
+	data, err := json.Marshal(schema)
+
+	assert.NoError(t, err)
+
+	imported, err := setting.ReadJSONSchema(strings.NewReader(string(data)))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, g.Elements["port"], imported.Elements["port"])
+	assert.Equal(t, g.Elements["hosts"], imported.Elements["hosts"])
+	assert.Equal(t, g.Subgroups["log"].Elements, imported.Subgroups["log"].Elements)
+}
+
+func TestFromJSONSchemaExportImport(t *testing.T) {
+	token := setting.NewElement(value.NewString(""))
+	token.Sensitive = true
+	token.Required = true
+
+	tenant := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"rate": setting.NewElement(value.NewFloat(1),
+				constraint.NewNot(constraint.NewLess(value.NewFloat(0))),
+				constraint.NewGreaterEqual(value.NewFloat(0)),
+			),
+		},
+	}
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"token": token,
+			"mode": setting.NewElement(value.NewString("a"),
+				constraint.NewAnyOf(
+					constraint.NewOneOf(value.NewStringSlice("a", "b")),
+					constraint.NewAllOf(constraint.NewMinLen(3), constraint.NewPattern(regexp.MustCompile("^x"))),
+				),
+				constraint.NewNoneOf(value.NewStringSlice("c")),
+			),
+			"ids": setting.NewElement(value.NewIntSlice(1),
+				constraint.NewContains(value.NewInt(1)),
+				constraint.NewUnique(),
+				constraint.NewEach(constraint.NewNoneOf(value.NewIntSlice(0))),
+				constraint.NewAnyOf(constraint.NewMaxLen(2), constraint.NewMinLen(5)),
+			),
+		},
+		Subgroups: map[string]*setting.Group{
+			"proxy": {
+				Optional: true,
+				Elements: map[string]*setting.Element{"host": setting.NewElement(value.NewString(""))},
+			},
+		},
+		Lists: map[string]*setting.List{
+			"upstreams": setting.NewList(&setting.Group{
+				Elements: map[string]*setting.Element{"port": setting.NewElement(value.NewInt(80))},
+			}, 1, 4),
+		},
+		Maps: map[string]*setting.Map{
+			"tenants": setting.NewMap(tenant, constraint.NewPattern(regexp.MustCompile("^[a-z]+$"))),
+		},
+	}
+
+	schema, warnings := g.JSONSchema()
+
+	assert.Empty(t, warnings)
+
+	data, err := json.Marshal(schema)
+
+	assert.NoError(t, err)
+
+	imported, err := setting.ReadJSONSchema(strings.NewReader(string(data)))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, imported.Elements["token"].Required)
+	assert.NotContains(t, imported.Elements, "token"+setting.FileSuffix)
+	assert.ElementsMatch(t, g.Elements["mode"].Constraints, imported.Elements["mode"].Constraints)
+	assert.ElementsMatch(t, g.Elements["ids"].Constraints, imported.Elements["ids"].Constraints)
+	assert.ElementsMatch(t, tenant.Elements["rate"].Constraints,
+		imported.Maps["tenants"].Template.Elements["rate"].Constraints)
+	assert.Equal(t, g.Maps["tenants"].KeyConstraints, imported.Maps["tenants"].KeyConstraints)
+
+	schema2, warnings := imported.JSONSchema()
+
+	assert.Empty(t, warnings)
+
+	data2, err := json.Marshal(schema2)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(data2))
+}
+
+func TestFromJSONSchemaSliceExamples(t *testing.T) {
+	tags := setting.NewElement(value.NewStringSlice("a"))
+	tags.Metadata.Examples = []string{"web, db"}
+	ports := setting.NewElement(value.NewIntSlice(80))
+	ports.Metadata.Examples = []string{"80,443"}
+
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{"tags": tags, "ports": ports},
+	}
+
+	schema, _ := g.JSONSchema()
+	data, err := json.Marshal(schema)
+
+	assert.NoError(t, err)
+
+	imported, err := setting.ReadJSONSchema(strings.NewReader(string(data)))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"web, db"}, imported.Elements["tags"].Metadata.Examples)
+	assert.Equal(t, []string{"80, 443"}, imported.Elements["ports"].Metadata.Examples)
+
+	schema2, _ := imported.JSONSchema()
+	data2, err := json.Marshal(schema2)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(data2))
+}
+
+func TestFromJSONSchemaUnsupported(t *testing.T) {
+	schema := map[string]interface{}{}
+	doc := `{
+		"type": "object",
+		"oneOf": [],
+		"properties": {
+			"port": {"type": "integer", "format": "int32", "minLength": 1},
+			"ids": {"type": "array", "items": {"type": "integer", "default": 1}, "contains": {}},
+			"a/b": {"type": ["string", "null"]},
+			"extra": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	}`
+
+	assert.NoError(t, json.Unmarshal([]byte(doc), &schema))
+
+	_, err := setting.FromJSONSchema(schema)
+
+	assert.True(t, errors.Is(err, setting.ErrUnsupportedSchema))
+	assert.Equal(t, setting.CodeUnsupportedSchema, setting.ErrorCode(err))
+
+	var unsupported *setting.UnsupportedSchemaError
+
+	assert.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, []string{
+		"/oneOf",
+		"/properties/a~1b/type",
+		"/properties/extra/additionalProperties",
+		"/properties/ids/contains",
+		"/properties/ids/items/default",
+		"/properties/port/format",
+		"/properties/port/minLength",
+	}, unsupported.Keywords)
+	assert.Equal(t, "unsupported JSON Schema keywords: /oneOf, /properties/a~1b/type, "+
+		"/properties/extra/additionalProperties, /properties/ids/contains, "+
+		"/properties/ids/items/default, /properties/port/format, /properties/port/minLength",
+		err.Error())
+}
+
+func TestFromJSONSchemaInvalid(t *testing.T) {
+	testCases := map[string]string{
+		`{"type": "string"}`:                                                    `expected object schema, got type "string"`,
+		`{"properties": {"x": {}}}`:                                             "/properties/x: schema has no type",
+		`{"properties": {"x": {"type": "null"}}}`:                               `/properties/x/type: unsupported type "null"`,
+		`{"properties": {"x": {"type": "integer", "minimum": "a"}}}`:            "/properties/x/minimum",
+		`{"properties": {"x": {"type": "integer", "default": "a"}}}`:            "/properties/x/default",
+		`{"properties": {"x": {"type": "string", "pattern": "("}}}`:             "/properties/x/pattern",
+		`{"properties": {"x": {"type": "array"}}}`:                              "/properties/x: array schema has no items",
+		`{"required": ["x"], "properties": {}}`:                                 `/required: property "x" is not defined`,
+		`{"properties": {"x": {"type": "integer", "enum": [1], "minimum": 0}}}`: "/properties/x: ",
+	}
+
+	for doc, expected := range testCases {
+		_, err := setting.ReadJSONSchema(strings.NewReader(doc))
+
+		if assert.Error(t, err, doc) {
+			assert.Contains(t, err.Error(), expected, doc)
+		}
+	}
+}
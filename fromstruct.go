@@ -0,0 +1,178 @@
+package setting
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// Struct tags used by FromStruct.
+const (
+	// NameTag overrides the element or subgroup name. A name of "-" skips the field.
+	NameTag = "setting"
+	// DescriptionTag sets Metadata.Description.
+	DescriptionTag = "desc"
+	// ExampleTag sets Metadata.Examples, separated by semicolons.
+	ExampleTag = "example"
+	// UnitTag sets Metadata.Unit.
+	UnitTag = "unit"
+	// SinceTag sets Metadata.Since.
+	SinceTag = "since"
+	// MetaTag sets Metadata.Tags, as comma-separated key=value pairs.
+	MetaTag = "meta"
+	// SensitiveTag marks the element as sensitive, if set to "true".
+	SensitiveTag = "sensitive"
+	// RequiredTag marks the element as required, if set to "true".
+	RequiredTag = "required"
+)
+
+// FromStruct makes a new group from a pointer to a struct. Each exported field
+// with a supported value type becomes an element whose value points to the
+// field, each struct field becomes a subgroup, each struct pointer field
+// becomes an optional subgroup that is present if the pointer is not nil, and
+// each struct slice field becomes a list (see List) with the struct slice
+// resized as needed. A map field with string keys and struct (or struct
+// pointer) values becomes a map (see Map). For a map of struct values, entry
+// values are copied into the Go map by Load, LoadFlat and Set. Element metadata
+// is taken from struct tags (see DescriptionTag etc.). Groups use declaration
+// order.
+// Returns a non-nil error if the argument is not a struct pointer or a field
+// type is not supported.
+func FromStruct(ptr interface{}) (*Group, error) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected non-nil struct pointer, got %T", ptr)
+	}
+
+	return groupFromStruct(rv.Elem(), []string{})
+}
+
+func groupFromStruct(rv reflect.Value, path []string) (*Group, error) {
+	g := &Group{
+		Elements:  map[string]*Element{},
+		Subgroups: map[string]*Group{},
+		Lists:     map[string]*List{},
+		Maps:      map[string]*Map{},
+		Ordering:  OrderDeclaration,
+		Order:     []string{},
+	}
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tagName, found := field.Tag.Lookup(NameTag); found {
+			if tagName == "-" {
+				continue
+			}
+
+			name = tagName
+		}
+
+		fieldPath := appendPath(path, name)
+		fieldVal := rv.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			subgroup, err := groupFromStruct(fieldVal, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+
+			g.Subgroups[name] = subgroup
+			g.Order = append(g.Order, name)
+
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+			subgroup, err := optionalFromStruct(fieldVal, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+
+			g.Subgroups[name] = subgroup
+			g.Order = append(g.Order, name)
+
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct {
+			list, err := listFromStruct(fieldVal, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+
+			g.Lists[name] = list
+			g.Order = append(g.Order, name)
+
+			continue
+		}
+
+		if isStructMap(field.Type) {
+			m, err := mapFromStruct(fieldVal, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+
+			g.Maps[name] = m
+			g.Order = append(g.Order, name)
+
+			continue
+		}
+
+		val := value.FromValue(fieldVal.Addr())
+		if val == nil {
+			return nil, fmt.Errorf("%s: field type %s is not supported",
+				strings.Join(fieldPath, "."), field.Type)
+		}
+
+		elem := NewElement(val)
+		elem.Metadata = metadataFromTag(field.Tag)
+		elem.Sensitive = field.Tag.Get(SensitiveTag) == "true"
+		elem.Required = field.Tag.Get(RequiredTag) == "true"
+
+		g.Elements[name] = elem
+		g.Order = append(g.Order, name)
+	}
+
+	return g, nil
+}
+
+// isStructMap returns true for a map type with string keys and struct
+// (or struct pointer) values.
+func isStructMap(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	return elem.Kind() == reflect.Struct
+}
+
+func metadataFromTag(tag reflect.StructTag) Metadata {
+	m := Metadata{
+		Description: tag.Get(DescriptionTag),
+		Unit:        tag.Get(UnitTag),
+		Since:       tag.Get(SinceTag),
+	}
+
+	if examples := tag.Get(ExampleTag); examples != "" {
+		m.Examples = strings.Split(examples, ";")
+	}
+
+	if meta := tag.Get(MetaTag); meta != "" {
+		m.Tags = parseTags(meta)
+	}
+
+	return m
+}
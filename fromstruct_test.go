@@ -0,0 +1,79 @@
+package setting_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTLS struct {
+	Port int64 `desc:"TLS port" example:"443;8443" since:"1.2"`
+}
+
+type testServer struct {
+	Host    string   `setting:"host" desc:"Host name"`
+	Timeout float64  `unit:"s" meta:"reload=true, owner = ops"`
+	Hosts   []string `setting:"hosts"`
+	TLS     testTLS  `setting:"tls"`
+	Ignored uint64   `setting:"-"`
+	hidden  int64
+}
+
+func TestFromStruct(t *testing.T) {
+	s := &testServer{Host: "localhost", TLS: testTLS{Port: 443}}
+	g, err := setting.FromStruct(s)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"Timeout", "host", "hosts"}, g.ElementNames())
+	assert.Equal(t, []string{"tls"}, g.SubgroupNames())
+
+	host := g.FindElement("host")
+
+	assert.Equal(t, value.TypeString, host.Value.Type())
+	assert.Equal(t, "localhost", host.Value.(value.Single).Value())
+	assert.Equal(t, "Host name", host.Metadata.Description)
+
+	timeout := g.FindElement("Timeout")
+
+	assert.Equal(t, "s", timeout.Metadata.Unit)
+	assert.Equal(t, map[string]string{"reload": "true", "owner": "ops"}, timeout.Metadata.Tags)
+
+	port := g.FindElement("tls", "Port")
+
+	assert.Equal(t, "TLS port", port.Metadata.Description)
+	assert.Equal(t, []string{"443", "8443"}, port.Metadata.Examples)
+	assert.Equal(t, "1.2", port.Metadata.Since)
+
+	// element values point to the struct fields
+	assert.NoError(t, port.Value.Parse("8443"))
+	assert.Equal(t, int64(8443), s.TLS.Port)
+}
+
+func TestFromStructNotStructPointer(t *testing.T) {
+	_, err := setting.FromStruct(testServer{})
+
+	assert.Error(t, err)
+
+	_, err = setting.FromStruct((*testServer)(nil))
+
+	assert.Error(t, err)
+}
+
+func TestFromStructUnsupportedField(t *testing.T) {
+	s := &struct {
+		Inner struct {
+			Count int
+		}
+	}{}
+
+	_, err := setting.FromStruct(s)
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Inner.Count")
+	}
+}
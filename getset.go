@@ -0,0 +1,522 @@
+package setting
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// Get returns the value of the element at the given dotted path, such as
+// "server.tls.port". A path ending in a slice index, such as "server.hosts[2]",
+// gives a single slice element, and an index on an earlier name gives a list
+// item, such as "upstreams[2].port". A map entry is given by the map name
+// followed by the key, such as "tenants.acme.rate_limit". Dots in names can
+// be escaped with a backslash. The old path of an alias gives the element at
+// the new path.
+// Returns a non-nil error if the path is invalid or not found.
+func (g *Group) Get(path string) (value.Value, error) {
+	elem, names, index, err := g.lookup(path, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if index == noIndex {
+		return elem.Value, nil
+	}
+
+	return sliceIndex(elem, names, index)
+}
+
+// Set parses the given string into the value of the element at the given
+// dotted path (see Get), and validates it. The element value only changes if
+// parsing and validation succeed. Optional subgroups in the path are not
+// made present (see Group.SetPresent).
+// Returns a non-nil error if the path is invalid or not found, or if parsing
+// or validation fails.
+func (g *Group) Set(path, str string) error {
+	elem, names, index, err := g.lookup(path, false, nil)
+	if err != nil {
+		return err
+	}
+
+	newVal := elem.Value.Clone()
+
+	if index == noIndex {
+		err = newVal.Parse(str)
+	} else {
+		err = parseIndex(newVal, names, index, str)
+	}
+
+	if err != nil {
+		return annotateError(err, names, elem.Sensitive)
+	}
+
+	check := &Element{Value: newVal, Constraints: elem.Constraints}
+	if err = check.Validate(); err != nil {
+		return annotateError(err, names, elem.Sensitive)
+	}
+
+	return g.trackChanges(func() error {
+		if err := copyValue(elem.Value, newVal); err != nil {
+			return err
+		}
+
+		g.syncMaps()
+
+		return nil
+	})
+}
+
+// GetInt returns the int64 value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetInt(path string) (int64, error) {
+	v, err := g.getSingle(path, value.TypeInt)
+	if err != nil {
+		return 0, err
+	}
+
+	return v.Value().(int64), nil
+}
+
+// GetUInt returns the uint64 value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetUInt(path string) (uint64, error) {
+	v, err := g.getSingle(path, value.TypeUInt)
+	if err != nil {
+		return 0, err
+	}
+
+	return v.Value().(uint64), nil
+}
+
+// GetFloat returns the float64 value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetFloat(path string) (float64, error) {
+	v, err := g.getSingle(path, value.TypeFloat)
+	if err != nil {
+		return 0.0, err
+	}
+
+	return v.Value().(float64), nil
+}
+
+// GetBool returns the bool value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetBool(path string) (bool, error) {
+	v, err := g.getSingle(path, value.TypeBool)
+	if err != nil {
+		return false, err
+	}
+
+	return v.Value().(bool), nil
+}
+
+// GetString returns the string value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetString(path string) (string, error) {
+	v, err := g.getSingle(path, value.TypeString)
+	if err != nil {
+		return "", err
+	}
+
+	return v.Value().(string), nil
+}
+
+// GetDuration returns a duration from the value at the given dotted path (see Get).
+// A string value is parsed using time.ParseDuration. A numeric value is
+// multiplied by the element unit (Metadata.Unit), which must be one of
+// ns, us, ms, s, m or h.
+// Returns a non-nil error if the path is not found or the value cannot be
+// converted to a duration, including when the duration would overflow.
+func (g *Group) GetDuration(path string) (time.Duration, error) {
+	elem, names, index, err := g.lookup(path, false, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	v := elem.Value
+	if index != noIndex {
+		if v, err = sliceIndex(elem, names, index); err != nil {
+			return 0, err
+		}
+	}
+
+	single, ok := v.(value.Single)
+	if !ok {
+		return 0, &value.TypeMismatchError{
+			Path: names, Expected: v.Type(), Actual: v.Type(), ActualSlice: true}
+	}
+
+	if str, ok := single.Value().(string); ok {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", strings.Join(names, "."), err)
+		}
+
+		return d, nil
+	}
+
+	unit, found := durationUnits[elem.Metadata.Unit]
+	if !found {
+		return 0, fmt.Errorf("%s: unit %q is not a duration unit",
+			strings.Join(names, "."), elem.Metadata.Unit)
+	}
+
+	switch x := single.Value().(type) {
+	case int64:
+		if x > math.MaxInt64/int64(unit) || x < math.MinInt64/int64(unit) {
+			return 0, durationOverflow(names, x, elem.Metadata.Unit)
+		}
+
+		return time.Duration(x) * unit, nil
+	case uint64:
+		if x > uint64(math.MaxInt64/int64(unit)) {
+			return 0, durationOverflow(names, x, elem.Metadata.Unit)
+		}
+
+		return time.Duration(x) * unit, nil
+	case float64:
+		// the float64 nearest to MaxInt64 is 2^63, which already overflows
+		d := x * float64(unit)
+		if math.IsNaN(d) || d >= math.MaxInt64 || d < math.MinInt64 {
+			return 0, durationOverflow(names, x, elem.Metadata.Unit)
+		}
+
+		return time.Duration(d), nil
+	}
+
+	return 0, &value.TypeMismatchError{Path: names, Expected: value.TypeString, Actual: v.Type()}
+}
+
+func durationOverflow(names []string, x interface{}, unit string) error {
+	return fmt.Errorf("%s: %v%s overflows a duration", strings.Join(names, "."), x, unit)
+}
+
+// GetIntSlice returns a copy of the int64 slice value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetIntSlice(path string) ([]int64, error) {
+	v, err := g.getSlice(path, value.TypeInt)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]int64{}, v.Slice().([]int64)...), nil
+}
+
+// GetUIntSlice returns a copy of the uint64 slice value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetUIntSlice(path string) ([]uint64, error) {
+	v, err := g.getSlice(path, value.TypeUInt)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]uint64{}, v.Slice().([]uint64)...), nil
+}
+
+// GetFloatSlice returns a copy of the float64 slice value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetFloatSlice(path string) ([]float64, error) {
+	v, err := g.getSlice(path, value.TypeFloat)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]float64{}, v.Slice().([]float64)...), nil
+}
+
+// GetBoolSlice returns a copy of the bool slice value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetBoolSlice(path string) ([]bool, error) {
+	v, err := g.getSlice(path, value.TypeBool)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]bool{}, v.Slice().([]bool)...), nil
+}
+
+// GetStringSlice returns a copy of the string slice value at the given dotted path (see Get).
+// Returns a non-nil error if the path is not found or the value has a different type.
+func (g *Group) GetStringSlice(path string) ([]string, error) {
+	v, err := g.getSlice(path, value.TypeString)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string{}, v.Slice().([]string)...), nil
+}
+
+func (g *Group) getSingle(path string, t value.Type) (value.Single, error) {
+	v, err := g.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	single, ok := v.(value.Single)
+	if !ok || v.Type() != t {
+		return nil, &value.TypeMismatchError{
+			Path: pathNames(path), Expected: t, Actual: v.Type(), ActualSlice: !ok}
+	}
+
+	return single, nil
+}
+
+func (g *Group) getSlice(path string, t value.Type) (value.Slice, error) {
+	v, err := g.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	slice, ok := v.(value.Slice)
+	if !ok || v.Type() != t {
+		return nil, &value.TypeMismatchError{
+			Path: pathNames(path), Expected: t, Actual: v.Type(),
+			ExpectedSlice: true, ActualSlice: ok}
+	}
+
+	return slice, nil
+}
+
+// lookup finds the element for a dotted path, returning it with the path
+// names and the slice index (or noIndex). An index on an earlier name gives
+// a list item, such as "upstreams[2].port". If grow is true, a list is resized
+// to include the item if it is the next item (up to the maximum number of
+// items), a map entry is added if needed, and optional subgroups are made
+// present. Aliases are resolved, with warnings appended if not nil.
+func (g *Group) lookup(
+	path string,
+	grow bool,
+	warnings *[]*DeprecationWarning,
+) (*Element, []string, int, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, nil, noIndex, err
+	}
+
+	names := segmentNames(segments)
+	group := g
+
+	for i := 0; i < len(segments); i++ {
+		if resolved, alias := group.resolveAlias(segments, i); alias != nil {
+			segments, names = resolved, segmentNames(resolved)
+
+			if warnings != nil {
+				*warnings = append(*warnings, alias.warning(names[:i]))
+			}
+		}
+
+		if i == len(segments)-1 {
+			break
+		}
+
+		segment := segments[i]
+
+		if segment.index == noIndex {
+			if m, found := group.Maps[segment.name]; found && i+1 < len(segments)-1 {
+				i++
+
+				key := segments[i]
+				if key.index != noIndex {
+					return nil, nil, noIndex, &NotFoundError{Path: names[:i+1], Index: noIndex}
+				}
+
+				entry, found := m.Entries[key.name]
+				if !found && !grow {
+					return nil, nil, noIndex, &NotFoundError{Path: names[:i+1], Index: noIndex}
+				}
+
+				if !found {
+					entry = m.Add(key.name)
+				}
+
+				group = entry
+
+				continue
+			}
+
+			subgroup, found := group.Subgroups[segment.name]
+			if !found {
+				return nil, nil, noIndex, &NotFoundError{Path: names[:i+1], Index: noIndex}
+			}
+
+			if grow && !subgroup.IsPresent() {
+				subgroup.SetPresent(true)
+			}
+
+			group = subgroup
+
+			continue
+		}
+
+		listPath := appendPath(names[:i], segment.name)
+
+		list, found := group.Lists[segment.name]
+		if !found {
+			return nil, nil, noIndex, &NotFoundError{Path: listPath, Index: noIndex}
+		}
+
+		if segment.index >= list.Len() {
+			if !grow {
+				return nil, nil, noIndex, &NotFoundError{Path: listPath, Index: segment.index}
+			}
+
+			// one item is added at a time, so that a large index cannot
+			// grow the list without bound
+			limit := list.Len() + 1
+			if list.MaxItems > 0 && list.MaxItems < limit {
+				limit = list.MaxItems
+			}
+
+			if segment.index >= limit {
+				return nil, nil, noIndex, &ItemCountError{
+					Path:     listPath,
+					Count:    segment.index + 1,
+					MaxItems: limit,
+				}
+			}
+
+			list.Resize(segment.index + 1)
+		}
+
+		group = list.Items[segment.index]
+	}
+
+	last := segments[len(segments)-1]
+
+	elem, found := group.Elements[last.name]
+	if !found {
+		return nil, nil, noIndex, &NotFoundError{Path: names, Index: noIndex}
+	}
+
+	return elem, names, last.index, nil
+}
+
+// resolveAlias replaces the path segments from index i by the new path of an
+// alias, if they start with its old path. A slice index on the last old name
+// is kept. Returns the alias, or nil if none matches.
+func (g *Group) resolveAlias(segments []pathSegment, i int) ([]pathSegment, *Alias) {
+	for _, alias := range g.Aliases {
+		n := len(alias.Old)
+		if n == 0 || len(alias.New) == 0 || i+n > len(segments) {
+			continue
+		}
+
+		matched := true
+
+		for k, name := range alias.Old {
+			segment := segments[i+k]
+			if segment.name != name || (segment.index != noIndex && k < n-1) {
+				matched = false
+
+				break
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		resolved := append([]pathSegment{}, segments[:i]...)
+		for _, name := range alias.New {
+			resolved = append(resolved, pathSegment{name: name, index: noIndex})
+		}
+
+		resolved[len(resolved)-1].index = segments[i+n-1].index
+
+		return append(resolved, segments[i+n:]...), alias
+	}
+
+	return segments, nil
+}
+
+// pathNames returns the names of a valid dotted path.
+func pathNames(path string) []string {
+	segments, _ := parsePath(path)
+
+	return segmentNames(segments)
+}
+
+// segmentNames returns the path names of parsed segments. List item names
+// include the index, but a final slice index is left out.
+func segmentNames(segments []pathSegment) []string {
+	names := make([]string, len(segments))
+
+	for i, segment := range segments {
+		names[i] = segment.name
+
+		if segment.index != noIndex && i < len(segments)-1 {
+			names[i] = itemName(segment.name, segment.index)
+		}
+	}
+
+	return names
+}
+
+func sliceIndex(elem *Element, names []string, index int) (value.Single, error) {
+	slice, ok := elem.Value.(value.Slice)
+	if !ok {
+		return nil, &value.TypeMismatchError{
+			Path: names, Expected: elem.Value.Type(), Actual: elem.Value.Type(), ExpectedSlice: true}
+	}
+
+	if index >= slice.Len() {
+		return nil, &NotFoundError{Path: names, Index: index}
+	}
+
+	return slice.Index(index), nil
+}
+
+// parseIndex parses a string into a slice element.
+func parseIndex(v value.Value, names []string, index int, str string) error {
+	slice, ok := v.(value.Slice)
+	if !ok {
+		return &value.TypeMismatchError{
+			Path: names, Expected: v.Type(), Actual: v.Type(), ExpectedSlice: true}
+	}
+
+	if index >= slice.Len() {
+		return &NotFoundError{Path: names, Index: index}
+	}
+
+	single := value.NewSingle(v.Type())
+	if err := single.Parse(str); err != nil {
+		return err
+	}
+
+	reflect.ValueOf(slice.SlicePointer()).Elem().Index(index).Set(reflect.ValueOf(single.Value()))
+
+	return nil
+}
+
+// copyValue copies the value from src to dest, which must have the same type.
+func copyValue(dest, src value.Value) error {
+	var destPtr, srcPtr interface{}
+
+	switch d := dest.(type) {
+	case value.Single:
+		destPtr, srcPtr = d.ValuePointer(), src.(value.Single).ValuePointer()
+	case value.Slice:
+		destPtr, srcPtr = d.SlicePointer(), src.(value.Slice).SlicePointer()
+	default:
+		return fmt.Errorf("unsupported value %T", dest)
+	}
+
+	reflect.ValueOf(destPtr).Elem().Set(reflect.ValueOf(srcPtr).Elem())
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package setting_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testGetSetTLS struct {
+	Port int64 `setting:"port"`
+}
+
+type testGetSet struct {
+	Hosts   []string      `setting:"hosts"`
+	Timeout string        `setting:"timeout"`
+	Delay   uint64        `setting:"delay" unit:"ms"`
+	Ratio   float64       `setting:"ratio"`
+	Debug   bool          `setting:"debug"`
+	Dotted  int64         `setting:"a.b"`
+	TLS     testGetSetTLS `setting:"tls"`
+	Weights []float64     `setting:"weights"`
+	Counts  []int64       `setting:"counts"`
+	Sizes   []uint64      `setting:"sizes"`
+	Flags   []bool        `setting:"flags"`
+}
+
+func newTestGetSetGroup(t *testing.T) (*setting.Group, *testGetSet) {
+	s := &testGetSet{
+		Hosts:   []string{"a", "b", "c"},
+		Timeout: "1m30s",
+		Delay:   250,
+		Ratio:   0.5,
+		Debug:   true,
+		Dotted:  7,
+		TLS:     testGetSetTLS{Port: 443},
+		Weights: []float64{0.5},
+		Counts:  []int64{-1},
+		Sizes:   []uint64{1},
+		Flags:   []bool{true},
+	}
+	g, err := setting.FromStruct(s)
+
+	assert.NoError(t, err)
+
+	g.FindElement("tls", "port").Constraints = []constraint.Constraint{
+		constraint.NewGreaterEqual(value.NewInt(1)),
+		constraint.NewLessEqual(value.NewInt(65535)),
+	}
+	g.FindElement("hosts").Constraints = []constraint.Constraint{
+		constraint.NewEach(constraint.NewMinLen(1)),
+	}
+
+	return g, s
+}
+
+func TestGroupGet(t *testing.T) {
+	g, _ := newTestGetSetGroup(t)
+
+	v, err := g.Get("tls.port")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(443), v.(value.Single).Value())
+
+	v, err = g.Get("hosts[1]")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "b", v.(value.Single).Value())
+
+	v, err = g.Get(`a\.b`)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), v.(value.Single).Value())
+
+	for _, path := range []string{"nope", "tls.nope", "nope.port", "hosts[3]", "tls[0].port"} {
+		_, err = g.Get(path)
+
+		assert.True(t, errors.Is(err, setting.ErrNotFound), path)
+	}
+
+	for _, path := range []string{"", "tls.", "hosts[x]", "hosts[1", "hosts[1]x", `tls\`} {
+		_, err = g.Get(path)
+
+		assert.Error(t, err, path)
+	}
+
+	_, err = g.Get("debug[0]")
+
+	assert.True(t, errors.Is(err, value.ErrTypeMismatch))
+}
+
+func TestGroupSet(t *testing.T) {
+	g, s := newTestGetSetGroup(t)
+
+	assert.NoError(t, g.Set("tls.port", "8443"))
+	assert.Equal(t, int64(8443), s.TLS.Port)
+
+	err := g.Set("tls.port", "70000")
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, []string{"tls", "port"}, setting.ErrorPath(err))
+	assert.Equal(t, int64(8443), s.TLS.Port)
+
+	err = g.Set("tls.port", "x")
+
+	assert.True(t, errors.Is(err, value.ErrParse))
+	assert.Equal(t, int64(8443), s.TLS.Port)
+
+	assert.NoError(t, g.Set("hosts[2]", "z"))
+	assert.Equal(t, []string{"a", "b", "z"}, s.Hosts)
+	assert.Error(t, g.Set("hosts[2]", ""))
+	assert.Equal(t, []string{"a", "b", "z"}, s.Hosts)
+	assert.True(t, errors.Is(g.Set("hosts[3]", "d"), setting.ErrNotFound))
+	assert.True(t, errors.Is(g.Set("nope", "d"), setting.ErrNotFound))
+	assert.True(t, errors.Is(g.Set("debug[0]", "true"), value.ErrTypeMismatch))
+
+	assert.NoError(t, g.Set("hosts", "x, y"))
+	assert.Equal(t, []string{"x", "y"}, s.Hosts)
+}
+
+func TestGroupTypedGetters(t *testing.T) {
+	g, _ := newTestGetSetGroup(t)
+
+	i, err := g.GetInt("tls.port")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(443), i)
+
+	u, err := g.GetUInt("delay")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(250), u)
+
+	f, err := g.GetFloat("ratio")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, f)
+
+	b, err := g.GetBool("debug")
+
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	str, err := g.GetString("hosts[0]")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a", str)
+
+	strs, err := g.GetStringSlice("hosts")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, strs)
+
+	floats, err := g.GetFloatSlice("weights")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.5}, floats)
+
+	ints, err := g.GetIntSlice("counts")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{-1}, ints)
+
+	uints, err := g.GetUIntSlice("sizes")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1}, uints)
+
+	bools, err := g.GetBoolSlice("flags")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true}, bools)
+
+	_, err = g.GetInt("ratio")
+
+	assert.True(t, errors.Is(err, value.ErrTypeMismatch))
+	assert.Equal(t, []string{"ratio"}, setting.ErrorPath(err))
+
+	_, err = g.GetString("hosts")
+
+	assert.True(t, errors.Is(err, value.ErrTypeMismatch))
+
+	_, err = g.GetStringSlice("timeout")
+
+	assert.True(t, errors.Is(err, value.ErrTypeMismatch))
+
+	_, err = g.GetBool("nope")
+
+	assert.True(t, errors.Is(err, setting.ErrNotFound))
+}
+
+func TestGroupGetDuration(t *testing.T) {
+	g, _ := newTestGetSetGroup(t)
+
+	d, err := g.GetDuration("timeout")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, d)
+
+	d, err = g.GetDuration("delay")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, d)
+
+	_, err = g.GetDuration("tls.port")
+
+	assert.Error(t, err)
+
+	_, err = g.GetDuration("hosts")
+
+	assert.Error(t, err)
+
+	_, err = g.GetDuration("hosts[0]")
+
+	assert.Error(t, err)
+}
+
+func TestGroupGetDurationOverflow(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"hours":   {Value: value.NewInt(10000000), Metadata: setting.Metadata{Unit: "h"}},
+			"past":    {Value: value.NewInt(-10000000), Metadata: setting.Metadata{Unit: "h"}},
+			"nanos":   {Value: value.NewUInt(math.MaxInt64 + 1), Metadata: setting.Metadata{Unit: "ns"}},
+			"seconds": {Value: value.NewFloat(1e12), Metadata: setting.Metadata{Unit: "s"}},
+			"max":     {Value: value.NewUInt(math.MaxInt64), Metadata: setting.Metadata{Unit: "ns"}},
+			"min":     {Value: value.NewInt(math.MinInt64), Metadata: setting.Metadata{Unit: "ns"}},
+		},
+		Subgroups: map[string]*setting.Group{},
+	}
+
+	_, err := g.GetDuration("hours")
+
+	assert.EqualError(t, err, "hours: 10000000h overflows a duration")
+
+	for _, path := range []string{"hours", "past", "nanos", "seconds"} {
+		_, err = g.GetDuration(path)
+
+		assert.Error(t, err, path)
+	}
+
+	d, err := g.GetDuration("max")
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(math.MaxInt64), d)
+
+	d, err = g.GetDuration("min")
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(math.MinInt64), d)
+}
+
+func TestJoinPath(t *testing.T) {
+	path := setting.JoinPath([]string{"a.b", "c[0]", `d\e`})
+
+	assert.Equal(t, `a\.b.c\[0\].d\\e`, path)
+}
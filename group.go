@@ -1,35 +1,236 @@
 package setting
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
 // MapByName is an alias
 type MapByName = map[string]*Group
 
-// Group and its elements represent a struct and its fields.
+// Group and its elements represent a struct and its fields. A group is not
+// safe for concurrent use while it is changed. Use a Store to share settings
+// between goroutines.
 type Group struct {
 	Elements  map[string]*Element
 	Subgroups map[string]*Group
+	// Lists are repeated subgroups
+	Lists map[string]*List
+	// Maps are subgroup maps with arbitrary keys
+	Maps map[string]*Map
+	// Aliases allow renamed elements to be loaded by their old paths
+	Aliases []*Alias
+	// Warnings are collected by the most recent load
+	Warnings []*DeprecationWarning
+	// Ordering determines the order that elements and subgroups are visited
+	Ordering Ordering
+	// Order lists element and subgroup names, for OrderDeclaration
+	Order []string
+	// Optional groups are only validated and exported when present (see SetPresent)
+	Optional bool
+
+	present bool
+	// setPresent updates the struct pointer, for optional groups made by FromStruct
+	setPresent func(present bool)
+	// subscriptionsMutex guards subscriptions, which is set by the first
+	// subscription, so a Store or Watcher can read it while others subscribe
+	subscriptionsMutex sync.Mutex
+	// subscriptions receive value changes (see OnChanges)
+	subscriptions *subscriptions
 }
 
-// FindElement searches for an element according to the given path.
+// FindElement looks up an element according to the given path. A list item
+// is given by a name with an index, such as "upstreams[2]", and a map entry
+// by the map name followed by the key.
 // Returns nil if the element is not found or the path is empty.
 func (g *Group) FindElement(path ...string) *Element {
-	switch len(path) {
-	case 0:
+	if len(path) == 0 {
 		return nil
-	case 1:
-		wantName := path[0]
-		for name, elem := range g.Elements {
-			if name == wantName {
-				return elem
+	}
+
+	for i := 0; i < len(path)-1; i++ {
+		if m, found := g.Maps[path[i]]; found && i+1 < len(path)-1 {
+			i++
+
+			if g = m.Entries[path[i]]; g == nil {
+				return nil
 			}
+
+			continue
+		}
+
+		if g = g.subgroupOrItem(path[i]); g == nil {
+			return nil
 		}
-	default:
-		wantName := path[0]
-		for name, subgroup := range g.Subgroups {
-			if name == wantName {
-				return subgroup.FindElement(path[1:]...)
+	}
+
+	return g.Elements[path[len(path)-1]]
+}
+
+// subgroupOrItem returns the subgroup or list item with the given path name.
+// Returns nil if not found.
+func (g *Group) subgroupOrItem(name string) *Group {
+	if subgroup, found := g.Subgroups[name]; found {
+		return subgroup
+	}
+
+	listName, index, ok := splitItemName(name)
+	if !ok {
+		return nil
+	}
+
+	list, found := g.Lists[listName]
+	if !found || index >= list.Len() {
+		return nil
+	}
+
+	return list.Items[index]
+}
+
+// Clone makes a deep copy of the group, where element values do not share
+// backing pointers with the original. Warnings and subscriptions are not copied.
+func (g *Group) Clone() *Group {
+	clone := &Group{
+		Elements:  make(map[string]*Element, len(g.Elements)),
+		Subgroups: make(map[string]*Group, len(g.Subgroups)),
+		Lists:     make(map[string]*List, len(g.Lists)),
+		Maps:      make(map[string]*Map, len(g.Maps)),
+		Aliases:   g.Aliases,
+		Ordering:  g.Ordering,
+		Order:     g.Order,
+		Optional:  g.Optional,
+		present:   g.present,
+	}
+
+	for name, elem := range g.Elements {
+		clone.Elements[name] = elem.Clone()
+	}
+
+	for name, subgroup := range g.Subgroups {
+		clone.Subgroups[name] = subgroup.Clone()
+	}
+
+	for name, list := range g.Lists {
+		listClone := NewList(list.Template.Clone(), list.MinItems, list.MaxItems)
+
+		for _, item := range list.Items {
+			listClone.Items = append(listClone.Items, item.Clone())
+		}
+
+		clone.Lists[name] = listClone
+	}
+
+	for name, m := range g.Maps {
+		mapClone := NewMap(m.Template.Clone(), m.KeyConstraints...)
+
+		for key, entry := range m.Entries {
+			mapClone.Entries[key] = entry.Clone()
+		}
+
+		clone.Maps[name] = mapClone
+	}
+
+	return clone
+}
+
+// CheckConstraints checks the constraints of all elements (including those in
+// subgroups), as in Element.CheckConstraints. Elements are checked in group order.
+// Returns non-nil error for the first element that fails, with the element path set.
+func (g *Group) CheckConstraints() error {
+	return g.visitElements([]string{}, (*Element).CheckConstraints)
+}
+
+// Validate checks the values of all elements (including those in subgroups,
+// list items and map entries) against their constraints, the number of items
+// in each list, and the keys of each map. Elements are checked in group order.
+// Optional subgroups that are absent are not validated.
+// Returns non-nil error for the first element, list or map key that fails
+// validation, with the path set. An *ItemCountError is returned for a list.
+func (g *Group) Validate() error {
+	if err := g.validateCollections([]string{}); err != nil {
+		return err
+	}
+
+	return g.walk([]string{}, annotateElementErrors((*Element).Validate),
+		func(path []string, subgroup *Group) error {
+			if err := skipAbsent(path, subgroup); err != nil {
+				return err
 			}
+
+			return subgroup.validateCollections(path)
+		})
+}
+
+// validateCollections checks the number of items in each list and the keys
+// of each map.
+func (g *Group) validateCollections(path []string) error {
+	for _, name := range g.ListNames() {
+		if err := g.Lists[name].checkItemCount(appendPath(path, name)); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range g.MapNames() {
+		if err := g.Maps[name].checkKeys(appendPath(path, name)); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+func (g *Group) visitElements(path []string, f func(*Element) error) error {
+	return g.walk(path, annotateElementErrors(f), nil)
+}
+
+// annotateElementErrors makes a walk function that annotates errors with the
+// element path.
+func annotateElementErrors(f func(*Element) error) WalkFunc {
+	return func(path []string, elem *Element) error {
+		if err := f(elem); err != nil {
+			return annotateError(err, path, elem.Sensitive)
+		}
+
+		return nil
+	}
+}
+
+// ElementNames returns the element names in sorted order.
+func (g *Group) ElementNames() []string {
+	names := make([]string, 0, len(g.Elements))
+	for name := range g.Elements {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// SubgroupNames returns the subgroup names in sorted order.
+func (g *Group) SubgroupNames() []string {
+	names := make([]string, 0, len(g.Subgroups))
+	for name := range g.Subgroups {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// String returns a line for each element (including those in subgroups), in
+// group order, with the element path and value. Sensitive values are redacted,
+// and absent optional subgroups are left out.
+func (g *Group) String() string {
+	var b strings.Builder
+
+	_ = g.walk([]string{}, func(path []string, e *Element) error {
+		fmt.Fprintf(&b, "%s = %s\n", strings.Join(path, "."), e)
+		return nil
+	}, skipAbsent)
+
+	return b.String()
+}
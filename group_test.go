@@ -55,3 +55,32 @@ func newTestGroup() *setting.Group {
 
 	return g
 }
+
+func TestGroupValidate(t *testing.T) {
+	g := &setting.Group{
+		Elements:  map[string]*setting.Element{},
+		Subgroups: map[string]*setting.Group{"X": newTestGroup()},
+	}
+
+	assert.NoError(t, g.Validate())
+	assert.Equal(t, []string{"X"}, g.SubgroupNames())
+	assert.Equal(t, []string{"A", "B"}, g.Subgroups["X"].ElementNames())
+
+	g.FindElement("X", "B").Value.(*value.Int).Set(20)
+
+	err := g.Validate()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "X")
+}
+
+func TestGroupString(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"token": {Value: value.NewString("abc"), Sensitive: true},
+		},
+		Subgroups: map[string]*setting.Group{"X": newTestGroup()},
+	}
+
+	assert.Equal(t, "token = ******\nX.A = 7.2\nX.B = 25\n", g.String())
+}
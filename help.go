@@ -0,0 +1,307 @@
+package setting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// DefaultHelpWidth is the line width used by Group.Help if none is given.
+const DefaultHelpWidth = 80
+
+// HelpOptions configures Group.Help.
+type HelpOptions struct {
+	// EnvPrefix is the prefix of environment variable names (see EnvName)
+	EnvPrefix string
+	// Width is the maximum line width, or zero for DefaultHelpWidth
+	Width int
+}
+
+// settingInfo describes an element for help text and documentation.
+// List indexes and map keys are shown as placeholders.
+type settingInfo struct {
+	// path has names such as "upstreams[N]" for list items and "<key>" for map keys
+	path []string
+	// env has environment variable name tokens, without the prefix
+	env []string
+	// flag has the escaped names of the flag path (see JoinPath)
+	flag []string
+	elem *Element
+	// optional is the path of the enclosing optional subgroup, if any
+	optional []string
+}
+
+// settingInfos returns info for each element in group order, including the
+// elements of list and map templates.
+func (g *Group) settingInfos() []*settingInfo {
+	infos := []*settingInfo{}
+
+	g.collectSettingInfos([]string{}, []string{}, []string{}, nil, &infos)
+
+	return infos
+}
+
+func (g *Group) collectSettingInfos(path, env, flag, optional []string, infos *[]*settingInfo) {
+	for _, name := range g.Names() {
+		namePath := appendPath(path, name)
+		nameEnv := appendPath(env, envToken(name))
+		nameFlag := appendPath(flag, JoinPath([]string{name}))
+
+		if elem, found := g.Elements[name]; found {
+			*infos = append(*infos, &settingInfo{
+				path: namePath, env: nameEnv, flag: nameFlag, elem: elem, optional: optional})
+
+			continue
+		}
+
+		if subgroup, found := g.Subgroups[name]; found {
+			subOptional := optional
+			if subgroup.Optional && subOptional == nil {
+				subOptional = namePath
+			}
+
+			subgroup.collectSettingInfos(namePath, nameEnv, nameFlag, subOptional, infos)
+
+			continue
+		}
+
+		if list, found := g.Lists[name]; found {
+			list.Template.collectSettingInfos(appendPath(path, name+"[N]"),
+				appendPath(nameEnv, "<N>"), appendPath(flag, JoinPath([]string{name})+"[N]"),
+				optional, infos)
+
+			continue
+		}
+
+		g.Maps[name].Template.collectSettingInfos(appendPath(namePath, "<key>"),
+			appendPath(nameEnv, "<KEY>"), appendPath(nameFlag, "<key>"), optional, infos)
+	}
+}
+
+func (info *settingInfo) pathString() string {
+	return strings.Join(info.path, ".")
+}
+
+func (info *settingInfo) envName(prefix string) string {
+	if prefix == "" {
+		return strings.Join(info.env, "_")
+	}
+
+	return prefix + "_" + strings.Join(info.env, "_")
+}
+
+func (info *settingInfo) flagName() string {
+	return "--" + strings.Join(info.flag, ".")
+}
+
+// typeString returns the element value type, with [] for a slice and the
+// unit (if any) in parentheses.
+func (info *settingInfo) typeString() string {
+	str := typeName(info.elem.Value)
+
+	if unit := info.elem.Metadata.Unit; unit != "" {
+		str += " (" + unit + ")"
+	}
+
+	return str
+}
+
+func typeName(v value.Value) string {
+	if v.IsSlice() {
+		return "[]" + v.Type().String()
+	}
+
+	return v.Type().String()
+}
+
+// Help returns usage text listing each setting (including those in subgroups
+// and list and map templates) in group order. Each setting has its path, type,
+// default (current) value, constraints, description, examples, deprecation
+// notes and the environment variable and flag names used by LoadEnv and
+// LoadFlat. Lines are wrapped to the width, and sensitive values are redacted.
+func (g *Group) Help(opts HelpOptions) string {
+	width := opts.Width
+	if width <= 0 {
+		width = DefaultHelpWidth
+	}
+
+	infos := g.settingInfos()
+
+	pathWidth := 0
+	for _, info := range infos {
+		if n := len(info.pathString()); n > pathWidth {
+			pathWidth = n
+		}
+	}
+
+	if pathWidth > width/3 {
+		pathWidth = width / 3
+	}
+
+	const indent = 2
+
+	detailIndent := indent + pathWidth + 2
+	detailWidth := width - detailIndent
+
+	var b strings.Builder
+
+	for i, info := range infos {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		lines := []string{}
+		for _, detail := range info.helpDetails(opts.EnvPrefix) {
+			lines = append(lines, wrapText(detail, detailWidth)...)
+		}
+
+		path := info.pathString()
+		fmt.Fprintf(&b, "%s%s", strings.Repeat(" ", indent), path)
+
+		if len(path) > pathWidth {
+			b.WriteString("\n")
+			b.WriteString(strings.Repeat(" ", detailIndent))
+		} else {
+			b.WriteString(strings.Repeat(" ", detailIndent-indent-len(path)))
+		}
+
+		for j, line := range lines {
+			if j > 0 {
+				b.WriteString(strings.Repeat(" ", detailIndent))
+			}
+
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// helpDetails returns the paragraphs describing a setting, starting with
+// the type and default value.
+func (info *settingInfo) helpDetails(envPrefix string) []string {
+	elem := info.elem
+	details := []string{fmt.Sprintf("%s (default: %s)", info.typeString(), elem)}
+
+	if desc := elem.Metadata.Description; desc != "" {
+		details = append(details, desc)
+	}
+
+	if constraints := describeConstraints(elem.Constraints); len(constraints) > 0 {
+		details = append(details, "Constraints: "+strings.Join(constraints, ", "))
+	}
+
+	if elem.Required {
+		details = append(details, "Required")
+	}
+
+	if len(elem.Metadata.Examples) > 0 && !elem.Sensitive {
+		details = append(details, "Examples: "+strings.Join(elem.Metadata.Examples, "; "))
+	}
+
+	if info.optional != nil {
+		details = append(details, "Only used if "+strings.Join(info.optional, ".")+" is present")
+	}
+
+	if elem.Deprecated != nil {
+		details = append(details, "Deprecated: "+elem.Deprecated.warning(info.path).String())
+	}
+
+	details = append(details, "Env: "+info.envName(envPrefix), "Flag: "+info.flagName())
+
+	return details
+}
+
+// describeConstraints describes constraints in human form. Bounds are
+// combined into a range such as "1 <= x <= 65535", and sets are listed,
+// such as "one of: debug, info, warn".
+func describeConstraints(constraints []constraint.Constraint) []string {
+	var lower, upper constraint.Constraint
+
+	others := []string{}
+
+	for _, c := range constraints {
+		switch c.Type() {
+		case constraint.TypeGreater, constraint.TypeGreaterEqual:
+			lower = c
+		case constraint.TypeLess, constraint.TypeLessEqual:
+			upper = c
+		case constraint.TypeOneOf:
+			others = append(others, "one of: "+formatItems(c.Param()))
+		case constraint.TypeNoneOf:
+			others = append(others, "none of: "+formatItems(c.Param()))
+		default:
+			others = append(others, c.String())
+		}
+	}
+
+	var bounds string
+
+	switch {
+	case lower != nil && upper != nil:
+		bounds = fmt.Sprintf("%s %s x %s %s",
+			constraint.FormatValue(lower.Param()), boundOperator(lower),
+			boundOperator(upper), constraint.FormatValue(upper.Param()))
+	case lower != nil:
+		bounds = lower.String()
+	case upper != nil:
+		bounds = upper.String()
+	default:
+		return others
+	}
+
+	return append([]string{bounds}, others...)
+}
+
+// boundOperator returns the operator for a bound written as "lower op x op upper".
+func boundOperator(c constraint.Constraint) string {
+	switch c.Type() {
+	case constraint.TypeGreater, constraint.TypeLess:
+		return "<"
+	}
+
+	return "<="
+}
+
+// formatItems formats slice values separated by commas, without brackets.
+func formatItems(v value.Value) string {
+	slice, ok := v.(value.Slice)
+	if !ok {
+		return constraint.FormatValue(v)
+	}
+
+	items := make([]string, slice.Len())
+	for i := range items {
+		items[i] = constraint.FormatValue(slice.Index(i))
+	}
+
+	return strings.Join(items, ", ")
+}
+
+// wrapText splits text into lines of at most the given width, breaking
+// between words. Words longer than the width are not broken.
+func wrapText(text string, width int) []string {
+	lines := []string{}
+	line := ""
+
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len(line)+1+len(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,115 @@
+package setting_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testHelpUpstream struct {
+	Host string `setting:"host" desc:"Upstream host name"`
+}
+
+type testHelpTLS struct {
+	Cert string `setting:"cert"`
+}
+
+type testHelp struct {
+	Port      int64              `setting:"port" desc:"Port to listen on for incoming connections from clients of the service" example:"80;8080"`
+	Level     string             `setting:"level"`
+	Timeout   uint64             `setting:"timeout" unit:"ms"`
+	Token     string             `setting:"token" sensitive:"true" example:"abc"`
+	Regions   []string           `setting:"regions"`
+	TLS       *testHelpTLS       `setting:"tls"`
+	Upstreams []testHelpUpstream `setting:"upstreams"`
+}
+
+func TestHelp(t *testing.T) {
+	g, err := setting.FromStruct(&testHelp{Port: 8080, Level: "info", Token: "secret"})
+
+	assert.NoError(t, err)
+
+	g.Elements["port"].Constraints = []constraint.Constraint{
+		constraint.NewGreaterEqual(value.NewInt(1)),
+		constraint.NewLessEqual(value.NewInt(65535)),
+	}
+	g.Elements["level"].Constraints = []constraint.Constraint{
+		constraint.NewOneOf(value.NewStringSlice("debug", "info", "warn")),
+	}
+	g.Elements["timeout"].Constraints = []constraint.Constraint{
+		constraint.NewGreater(value.NewUInt(0)),
+		constraint.NewMultipleOf(value.NewUInt(10)),
+	}
+	g.Elements["regions"].Deprecated = &setting.Deprecation{Since: "1.2"}
+
+	expected := `  port               int64 (default: 8080)
+                     Port to listen on for incoming
+                     connections from clients of the service
+                     Constraints: 1 <= x <= 65535
+                     Examples: 80; 8080
+                     Env: APP_PORT
+                     Flag: --port
+
+  level              string (default: info)
+                     Constraints: one of: debug, info, warn
+                     Env: APP_LEVEL
+                     Flag: --level
+
+  timeout            uint64 (ms) (default: 0)
+                     Constraints: x > 0, x % 10 == 0
+                     Env: APP_TIMEOUT
+                     Flag: --timeout
+
+  token              string (default: ******)
+                     Env: APP_TOKEN
+                     Flag: --token
+
+  regions            []string (default: [])
+                     Deprecated: regions is deprecated since
+                     1.2
+                     Env: APP_REGIONS
+                     Flag: --regions
+
+  tls.cert           string (default: )
+                     Only used if tls is present
+                     Env: APP_TLS_CERT
+                     Flag: --tls.cert
+
+  upstreams[N].host  string (default: )
+                     Upstream host name
+                     Env: APP_UPSTREAMS_<N>_HOST
+                     Flag: --upstreams[N].host
+`
+
+	assert.Equal(t, expected, g.Help(setting.HelpOptions{EnvPrefix: "APP", Width: 60}))
+}
+
+func TestHelpDefaults(t *testing.T) {
+	tenants := &testTenants{}
+	g, _ := setting.FromStruct(tenants)
+
+	expected := `  tenants.<key>.rate_limit  int64 (default: 0)
+                            Env: TENANTS_<KEY>_RATE_LIMIT
+                            Flag: --tenants.<key>.rate_limit
+`
+
+	help := g.Help(setting.HelpOptions{})
+
+	assert.Contains(t, help, expected)
+	assert.Contains(t, help, "regions.<key>.region")
+}
+
+func TestHelpFlagEscaped(t *testing.T) {
+	g := &setting.Group{
+		Subgroups: map[string]*setting.Group{
+			"log.v2": {
+				Elements: map[string]*setting.Element{"level": setting.NewElement(value.NewString(""))},
+			},
+		},
+	}
+
+	assert.Contains(t, g.Help(setting.HelpOptions{}), `Flag: --log\.v2.level`)
+}
@@ -0,0 +1,403 @@
+package setting
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// JSONSchemaDraft is the meta-schema of schemas made by Group.JSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// SchemaWarning reports a constraint or an alias that cannot be represented
+// in a schema, and so was left out.
+type SchemaWarning struct {
+	Path []string
+	// Constraint is nil for an alias
+	Constraint constraint.Constraint
+	// Alias is nil for a constraint
+	Alias *Alias
+}
+
+// String returns a readable warning message.
+func (w *SchemaWarning) String() string {
+	if w.Alias != nil {
+		return fmt.Sprintf("%s: alias of %s cannot be represented",
+			strings.Join(w.Path, "."), strings.Join(w.Alias.New, "."))
+	}
+
+	return fmt.Sprintf("%s: constraint %s cannot be represented",
+		strings.Join(w.Path, "."), w.Constraint)
+}
+
+// JSONSchema makes a JSON Schema (draft 2020-12) document for the group, which
+// can be encoded using encoding/json. Subgroups become nested objects, lists
+// become arrays of objects and maps become objects with additional properties.
+// Required elements (and subgroups and lists that need them) are listed in
+// "required", and element values are given as defaults unless sensitive.
+// Constraints that cannot be represented, such as Func, Sorted and
+// SumLessEqual, are left out and reported by the returned warnings, as are
+// aliases other than from an old name to an element. Unsigned integers become
+// integers with a minimum of 0. MinLen and MaxLen on a string become
+// minLength and maxLength, which count characters where the constraints
+// count bytes, so they only agree for ASCII strings.
+func (g *Group) JSONSchema() (map[string]interface{}, []*SchemaWarning) {
+	warnings := []*SchemaWarning{}
+
+	schema := g.objectSchema([]string{}, &warnings)
+	schema["$schema"] = JSONSchemaDraft
+
+	return schema, warnings
+}
+
+func (g *Group) objectSchema(path []string, warnings *[]*SchemaWarning) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	allOf := []interface{}{}
+
+	for _, name := range g.Names() {
+		namePath := appendPath(path, name)
+
+		if elem, found := g.Elements[name]; found {
+			properties[name] = elem.jsonSchema(namePath, warnings)
+
+			switch {
+			case elem.Sensitive:
+				properties[name+FileSuffix] = map[string]interface{}{"type": "string"}
+
+				if elem.Required {
+					allOf = append(allOf, map[string]interface{}{"anyOf": []interface{}{
+						map[string]interface{}{"required": []string{name}},
+						map[string]interface{}{"required": []string{name + FileSuffix}},
+					}})
+				}
+			case elem.Required:
+				required = append(required, name)
+			}
+
+			continue
+		}
+
+		if subgroup, found := g.Subgroups[name]; found {
+			subschema := subgroup.objectSchema(namePath, warnings)
+
+			if subgroup.Optional {
+				subschema["type"] = []string{"object", "null"}
+			} else if _, found := subschema["required"]; found {
+				required = append(required, name)
+			}
+
+			properties[name] = subschema
+
+			continue
+		}
+
+		if list, found := g.Lists[name]; found {
+			subschema := map[string]interface{}{
+				"type":  "array",
+				"items": list.Template.objectSchema(appendPath(path, name+"[]"), warnings),
+			}
+
+			if list.MinItems > 0 {
+				subschema["minItems"] = list.MinItems
+				required = append(required, name)
+			}
+
+			if list.MaxItems > 0 {
+				subschema["maxItems"] = list.MaxItems
+			}
+
+			properties[name] = subschema
+
+			continue
+		}
+
+		m := g.Maps[name]
+		subschema := map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": m.Template.objectSchema(appendPath(namePath, "*"), warnings),
+		}
+
+		if len(m.KeyConstraints) > 0 {
+			subschema["propertyNames"] = constraintsSchema(
+				m.KeyConstraints, value.NewString(""), namePath, warnings)
+		}
+
+		properties[name] = subschema
+	}
+
+	for _, alias := range g.Aliases {
+		elem := g.FindElement(alias.New...)
+		if elem == nil || len(alias.Old) != 1 {
+			// only an old name in this group can be a property
+			*warnings = append(*warnings, &SchemaWarning{Path: appendPath(path, alias.Old...), Alias: alias})
+
+			continue
+		}
+
+		aliasSchema := elem.jsonSchema(appendPath(path, alias.Old...), &[]*SchemaWarning{})
+		aliasSchema["deprecated"] = true
+		delete(aliasSchema, "default")
+		properties[alias.Old[0]] = aliasSchema
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	if len(allOf) > 0 {
+		schema["allOf"] = allOf
+	}
+
+	return schema
+}
+
+func (e *Element) jsonSchema(path []string, warnings *[]*SchemaWarning) map[string]interface{} {
+	schema := constraintsSchema(e.Constraints, e.Value, path, warnings)
+
+	addTypeSchema(schema, valueTypeSchema(e.Value))
+
+	if e.Metadata.Description != "" {
+		schema["description"] = e.Metadata.Description
+	}
+
+	if e.Sensitive {
+		schema["writeOnly"] = true
+	} else {
+		schema["default"] = jsonValue(e.Value)
+
+		examples := []interface{}{}
+
+		for _, example := range e.Metadata.Examples {
+			v := e.Value.Clone()
+			if err := v.Parse(example); err == nil {
+				examples = append(examples, jsonValue(v))
+			}
+		}
+
+		if len(examples) > 0 {
+			schema["examples"] = examples
+		}
+	}
+
+	if e.Deprecated != nil {
+		schema["deprecated"] = true
+	}
+
+	return schema
+}
+
+// valueTypeSchema returns the schema keywords for a value type. For a slice,
+// the items schema is only given the item type.
+func valueTypeSchema(v value.Value) map[string]interface{} {
+	var schema map[string]interface{}
+
+	switch v.Type() {
+	case value.TypeInt:
+		schema = map[string]interface{}{"type": "integer"}
+	case value.TypeUInt:
+		schema = map[string]interface{}{"type": "integer", "minimum": 0}
+	case value.TypeFloat:
+		schema = map[string]interface{}{"type": "number"}
+	case value.TypeBool:
+		schema = map[string]interface{}{"type": "boolean"}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+	}
+
+	if !v.IsSlice() {
+		return schema
+	}
+
+	return map[string]interface{}{"type": "array", "items": schema}
+}
+
+// constraintsSchema makes a schema with the constraints applied to the value.
+// For a slice value, constraints on each slice element go in the items schema,
+// which is also given the item type.
+func constraintsSchema(
+	constraints []constraint.Constraint,
+	v value.Value,
+	path []string,
+	warnings *[]*SchemaWarning,
+) map[string]interface{} {
+	schema := map[string]interface{}{}
+
+	for _, c := range constraints {
+		fragment, ok := constraintSchema(c, v)
+		if !ok {
+			*warnings = append(*warnings, &SchemaWarning{Path: path, Constraint: c})
+
+			continue
+		}
+
+		mergeSchema(schema, fragment)
+	}
+
+	if items, found := schema["items"].(map[string]interface{}); found {
+		addTypeSchema(items, valueTypeSchema(value.NewSingle(v.Type())))
+	}
+
+	return schema
+}
+
+// addTypeSchema adds type keywords to a schema, keeping any keywords already
+// given by constraints (such as a minimum for an unsigned integer).
+func addTypeSchema(schema, typeSchema map[string]interface{}) {
+	for key, val := range typeSchema {
+		existing, found := schema[key]
+
+		switch {
+		case !found:
+			schema[key] = val
+		case key == "items":
+			addTypeSchema(existing.(map[string]interface{}), val.(map[string]interface{}))
+		}
+	}
+}
+
+// mergeSchema adds the keywords of a schema fragment, using allOf for
+// keywords that are already present.
+func mergeSchema(schema, fragment map[string]interface{}) {
+	for key, val := range fragment {
+		if _, found := schema[key]; !found {
+			schema[key] = val
+			continue
+		}
+
+		if key == "items" {
+			mergeSchema(schema[key].(map[string]interface{}), val.(map[string]interface{}))
+			continue
+		}
+
+		allOf, _ := schema["allOf"].([]interface{})
+		schema["allOf"] = append(allOf, map[string]interface{}{key: val})
+	}
+}
+
+// constraintSchema makes a schema fragment for a constraint applied to the
+// value. Returns false if the constraint cannot be represented.
+func constraintSchema(c constraint.Constraint, v value.Value) (map[string]interface{}, bool) {
+	var keyword string
+
+	switch c.Type() {
+	case constraint.TypeGreater:
+		keyword = "exclusiveMinimum"
+	case constraint.TypeGreaterEqual:
+		keyword = "minimum"
+	case constraint.TypeLess:
+		keyword = "exclusiveMaximum"
+	case constraint.TypeLessEqual:
+		keyword = "maximum"
+	case constraint.TypeMultipleOf:
+		keyword = "multipleOf"
+	case constraint.TypePattern:
+		keyword = "pattern"
+	case constraint.TypeOneOf:
+		keyword = "enum"
+	case constraint.TypeNoneOf:
+		return itemsSchema(v, map[string]interface{}{
+			"not": map[string]interface{}{"enum": jsonValue(c.Param())}}), true
+	case constraint.TypeMinLen, constraint.TypeMaxLen:
+		return lenSchema(c, v), true
+	case constraint.TypeUnique:
+		return map[string]interface{}{"uniqueItems": true}, true
+	case constraint.TypeContains:
+		return map[string]interface{}{
+			"contains": map[string]interface{}{"const": jsonValue(c.Param())}}, true
+	case constraint.TypeEach:
+		items := map[string]interface{}{}
+		single := value.NewSingle(v.Type())
+
+		for _, c2 := range c.(constraint.Composite).Constraints() {
+			fragment, ok := constraintSchema(c2, single)
+			if !ok {
+				return nil, false
+			}
+
+			mergeSchema(items, fragment)
+		}
+
+		return map[string]interface{}{"items": items}, true
+	case constraint.TypeAllOf, constraint.TypeAnyOf, constraint.TypeNot:
+		return compositeSchema(c, v)
+	default:
+		return nil, false
+	}
+
+	return itemsSchema(v, map[string]interface{}{keyword: jsonValue(c.Param())}), true
+}
+
+// itemsSchema puts a fragment in the items schema for a slice value.
+func itemsSchema(v value.Value, fragment map[string]interface{}) map[string]interface{} {
+	if v.IsSlice() {
+		return map[string]interface{}{"items": fragment}
+	}
+
+	return fragment
+}
+
+func lenSchema(c constraint.Constraint, v value.Value) map[string]interface{} {
+	keyword := "minLength"
+
+	switch {
+	case v.IsSlice() && c.Type() == constraint.TypeMinLen:
+		keyword = "minItems"
+	case v.IsSlice():
+		keyword = "maxItems"
+	case c.Type() == constraint.TypeMaxLen:
+		keyword = "maxLength"
+	}
+
+	return map[string]interface{}{keyword: jsonValue(c.Param())}
+}
+
+func compositeSchema(c constraint.Constraint, v value.Value) (map[string]interface{}, bool) {
+	subschemas := []interface{}{}
+
+	for _, c2 := range c.(constraint.Composite).Constraints() {
+		fragment, ok := constraintSchema(c2, v)
+		if !ok {
+			return nil, false
+		}
+
+		subschemas = append(subschemas, fragment)
+	}
+
+	switch c.Type() {
+	case constraint.TypeAllOf:
+		return map[string]interface{}{"allOf": subschemas}, true
+	case constraint.TypeAnyOf:
+		return map[string]interface{}{"anyOf": subschemas}, true
+	}
+
+	return map[string]interface{}{"not": subschemas[0]}, true
+}
+
+// jsonValue converts a value to a JSON-compatible value.
+func jsonValue(v value.Value) interface{} {
+	switch vv := v.(type) {
+	case value.Single:
+		return vv.Value()
+	case value.Slice:
+		rv := reflect.ValueOf(vv.Slice())
+		items := make([]interface{}, rv.Len())
+
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+
+		return items
+	}
+
+	return nil
+}
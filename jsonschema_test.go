@@ -0,0 +1,226 @@
+package setting_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSchemaUpstream struct {
+	Host string `setting:"host" required:"true"`
+}
+
+type testSchemaTLS struct {
+	Cert string `setting:"cert" required:"true"`
+}
+
+type testSchema struct {
+	Port      uint64                        `setting:"port" desc:"Port to listen on" required:"true" example:"80"`
+	Level     string                        `setting:"level"`
+	Token     string                        `setting:"token" sensitive:"true"`
+	Regions   []string                      `setting:"regions"`
+	TLS       *testSchemaTLS                `setting:"tls"`
+	Upstreams []testSchemaUpstream          `setting:"upstreams"`
+	Tenants   map[string]testSchemaUpstream `setting:"tenants"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	g, err := setting.FromStruct(&testSchema{Port: 8080, Level: "info", Regions: []string{"eu"}})
+
+	assert.NoError(t, err)
+
+	g.Elements["port"].Constraints = []constraint.Constraint{
+		constraint.NewGreaterEqual(value.NewUInt(1)),
+		constraint.NewLessEqual(value.NewUInt(65535)),
+	}
+	g.Elements["level"].Constraints = []constraint.Constraint{
+		constraint.NewOneOf(value.NewStringSlice("debug", "info")),
+	}
+	g.Elements["regions"].Constraints = []constraint.Constraint{
+		constraint.NewMinLen(1),
+		constraint.NewUnique(),
+		constraint.NewEach(constraint.NewMaxLen(8)),
+	}
+	g.Lists["upstreams"].MinItems = 1
+	g.Maps["tenants"].KeyConstraints = []constraint.Constraint{
+		constraint.NewNoneOf(value.NewStringSlice("default")),
+	}
+
+	schema, warnings := g.JSONSchema()
+
+	assert.Empty(t, warnings)
+
+	data, err := json.Marshal(schema)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["port", "upstreams"],
+		"properties": {
+			"port": {
+				"type": "integer", "minimum": 1, "maximum": 65535, "default": 8080,
+				"description": "Port to listen on", "examples": [80]
+			},
+			"level": {"type": "string", "enum": ["debug", "info"], "default": "info"},
+			"token": {"type": "string", "writeOnly": true},
+			"token_file": {"type": "string"},
+			"regions": {
+				"type": "array", "minItems": 1, "uniqueItems": true,
+				"items": {"type": "string", "maxLength": 8}, "default": ["eu"]
+			},
+			"tls": {
+				"type": ["object", "null"],
+				"additionalProperties": false,
+				"required": ["cert"],
+				"properties": {"cert": {"type": "string", "default": ""}}
+			},
+			"upstreams": {
+				"type": "array",
+				"minItems": 1,
+				"items": {
+					"type": "object",
+					"additionalProperties": false,
+					"required": ["host"],
+					"properties": {"host": {"type": "string", "default": ""}}
+				}
+			},
+			"tenants": {
+				"type": "object",
+				"propertyNames": {"not": {"enum": ["default"]}},
+				"additionalProperties": {
+					"type": "object",
+					"additionalProperties": false,
+					"required": ["host"],
+					"properties": {"host": {"type": "string", "default": ""}}
+				}
+			}
+		}
+	}`, string(data))
+}
+
+func TestJSONSchemaConstraints(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"ratio": setting.NewElement(value.NewFloat(0.5),
+				constraint.NewGreater(value.NewFloat(0)),
+				constraint.NewAnyOf(
+					constraint.NewLess(value.NewFloat(1)),
+					constraint.NewNot(constraint.NewMultipleOf(value.NewFloat(2))),
+				),
+			),
+			"counts": setting.NewElement(value.NewIntSlice(1, 2),
+				constraint.NewLessEqual(value.NewInt(10)),
+				constraint.NewLessEqual(value.NewInt(5)),
+				constraint.NewContains(value.NewInt(1)),
+			),
+		},
+	}
+
+	schema, warnings := g.JSONSchema()
+
+	assert.Empty(t, warnings)
+
+	data, err := json.Marshal(schema["properties"])
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"ratio": {
+			"type": "number", "default": 0.5, "exclusiveMinimum": 0,
+			"anyOf": [{"exclusiveMaximum": 1}, {"not": {"multipleOf": 2}}]
+		},
+		"counts": {
+			"type": "array", "default": [1, 2],
+			"contains": {"const": 1},
+			"items": {"type": "integer", "maximum": 10, "allOf": [{"maximum": 5}]}
+		}
+	}`, string(data))
+}
+
+func TestJSONSchemaWarnings(t *testing.T) {
+	check := func(v value.Value) error { return nil }
+	funcConstraint := constraint.NewFunc("even", "x is even", check, value.TypeInt)
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"port": setting.NewElement(value.NewInt(1),
+				constraint.NewGreater(value.NewInt(0)),
+				funcConstraint,
+			),
+		},
+		Subgroups: map[string]*setting.Group{
+			"sub": {
+				Elements: map[string]*setting.Element{
+					"ids": setting.NewElement(value.NewIntSlice(),
+						constraint.NewSortedAscending(),
+						constraint.NewAnyOf(constraint.NewUnique(), funcConstraint),
+					),
+				},
+			},
+		},
+	}
+
+	schema, warnings := g.JSONSchema()
+
+	assert.Equal(t, map[string]interface{}{
+		"type": "integer", "default": int64(1), "exclusiveMinimum": int64(0),
+	}, schema["properties"].(map[string]interface{})["port"])
+
+	if !assert.Len(t, warnings, 3) {
+		return
+	}
+
+	assert.Equal(t, []string{"port"}, warnings[0].Path)
+	assert.Same(t, funcConstraint, warnings[0].Constraint)
+	assert.Equal(t, []string{"sub", "ids"}, warnings[1].Path)
+	assert.Equal(t, constraint.TypeSorted, warnings[1].Constraint.Type())
+	assert.Equal(t, constraint.TypeAnyOf, warnings[2].Constraint.Type())
+	assert.Contains(t, warnings[0].String(), "port: constraint ")
+}
+
+func TestJSONSchemaAliasWarnings(t *testing.T) {
+	g, _ := newTestServiceGroup(t)
+
+	schema, warnings := g.JSONSchema()
+	properties := schema["properties"].(map[string]interface{})
+
+	assert.Contains(t, properties, "port")
+	assert.NotContains(t, properties, "legacy")
+
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, []string{"legacy", "host"}, warnings[0].Path)
+		assert.Nil(t, warnings[0].Constraint)
+		assert.Equal(t, "legacy.host: alias of listener.host cannot be represented", warnings[0].String())
+	}
+}
+
+func TestJSONSchemaRequiredSensitive(t *testing.T) {
+	token := setting.NewElement(value.NewString("secret"))
+	token.Sensitive = true
+	token.Required = true
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{"token": token},
+		Aliases:  []*setting.Alias{{Old: []string{"api_token"}, New: []string{"token"}}},
+	}
+
+	schema, _ := g.JSONSchema()
+
+	data, err := json.Marshal(schema)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"token": {"type": "string", "writeOnly": true},
+			"token_file": {"type": "string"},
+			"api_token": {"type": "string", "writeOnly": true, "deprecated": true}
+		},
+		"allOf": [{"anyOf": [{"required": ["token"]}, {"required": ["token_file"]}]}]
+	}`, string(data))
+}
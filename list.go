@@ -0,0 +1,234 @@
+package setting
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// List is a repeated subgroup. Each item is a group with the same elements,
+// constraints and metadata as the template. Changes to the template apply to
+// new items, or to existing items by calling ApplyTemplate.
+type List struct {
+	// Template is the group schema for each item. It is not validated itself.
+	Template *Group
+	// Items are the list items, which are visited with paths such as "upstreams[2]"
+	Items []*Group
+	// MinItems is the minimum number of items
+	MinItems int
+	// MaxItems is the maximum number of items, or zero for no maximum
+	MaxItems int
+
+	// resize replaces cloning the template, for lists made by FromStruct
+	resize func(n int)
+}
+
+// NewList makes a new list with no items.
+func NewList(template *Group, minItems, maxItems int) *List {
+	return &List{
+		Template: template,
+		Items:    []*Group{},
+		MinItems: minItems,
+		MaxItems: maxItems,
+	}
+}
+
+// Len returns the number of items.
+func (l *List) Len() int {
+	return len(l.Items)
+}
+
+// Resize changes the number of items. Existing items are kept, up to the new
+// length, and new items are cloned from the template.
+// For a list made by FromStruct, the struct slice is resized, and all items are
+// remade if it needs a larger backing array, so previously returned items
+// should not be used after growing the list.
+func (l *List) Resize(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	if l.resize != nil {
+		l.resize(n)
+
+		return
+	}
+
+	if n <= len(l.Items) {
+		l.Items = l.Items[:n]
+
+		return
+	}
+
+	for len(l.Items) < n {
+		l.Items = append(l.Items, l.Template.Clone())
+	}
+}
+
+// ApplyTemplate updates existing items after a change to the template, copying
+// everything but element values, such as constraints and metadata.
+func (l *List) ApplyTemplate() {
+	for _, item := range l.Items {
+		item.applyTemplate(l.Template)
+	}
+}
+
+// checkItemCount returns a non-nil *ItemCountError if the number of items
+// is out of range.
+func (l *List) checkItemCount(path []string) error {
+	n := len(l.Items)
+
+	if n < l.MinItems || (l.MaxItems > 0 && n > l.MaxItems) {
+		return &ItemCountError{Path: path, Count: n, MinItems: l.MinItems, MaxItems: l.MaxItems}
+	}
+
+	return nil
+}
+
+// ListNames returns the list names in sorted order.
+func (g *Group) ListNames() []string {
+	names := make([]string, 0, len(g.Lists))
+	for name := range g.Lists {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// itemName makes the path name of a list item, such as "upstreams[2]".
+func itemName(name string, index int) string {
+	return fmt.Sprintf("%s[%d]", name, index)
+}
+
+// splitItemName splits a list item path name into the list name and index.
+// Returns false if the name does not have an index.
+func splitItemName(name string) (string, int, bool) {
+	start := strings.LastIndexByte(name, '[')
+	if start <= 0 || !strings.HasSuffix(name, "]") {
+		return "", noIndex, false
+	}
+
+	index, err := strconv.Atoi(name[start+1 : len(name)-1])
+	if err != nil || index < 0 {
+		return "", noIndex, false
+	}
+
+	return name[:start], index, true
+}
+
+// listFromStruct makes a list from a struct slice. Items are made from the
+// slice elements, and remade only when the slice gets a new backing array.
+func listFromStruct(rv reflect.Value, path []string) (*List, error) {
+	template, err := groupFromStruct(reflect.New(rv.Type().Elem()).Elem(), appendPath(path, "[]"))
+	if err != nil {
+		return nil, err
+	}
+
+	l := NewList(template, 0, 0)
+
+	// array is the backing array of the slice that the items were made from
+	var array uintptr
+
+	makeItems := func(from int) {
+		l.Items = l.Items[:from]
+
+		for i := from; i < rv.Len(); i++ {
+			// the item type was already checked when making the template
+			item, _ := groupFromStruct(rv.Index(i), path)
+			item.applyTemplate(l.Template)
+
+			l.Items = append(l.Items, item)
+		}
+
+		array = rv.Pointer()
+	}
+
+	l.resize = func(n int) {
+		length := rv.Len()
+		from := length
+
+		if rv.Pointer() != array || from != len(l.Items) {
+			// the slice was replaced, so no item can be kept
+			from = 0
+		}
+
+		if n <= rv.Cap() {
+			rv.Set(rv.Slice(0, n))
+
+			// elements past the old length may hold removed items
+			for i := length; i < n; i++ {
+				rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+			}
+		} else {
+			// the capacity is doubled, so that items are remade only
+			// once in a while when growing one item at a time
+			capacity := 2 * rv.Cap()
+			if capacity < n {
+				capacity = n
+			}
+
+			newSlice := reflect.MakeSlice(rv.Type(), n, capacity)
+
+			reflect.Copy(newSlice, rv)
+			rv.Set(newSlice)
+
+			from = 0
+		}
+
+		if from > n {
+			from = n
+		}
+
+		makeItems(from)
+	}
+
+	makeItems(0)
+
+	return l, nil
+}
+
+// applyTemplate copies everything but the element values from a template
+// group with the same structure.
+func (g *Group) applyTemplate(template *Group) {
+	g.Aliases = template.Aliases
+	g.Ordering = template.Ordering
+	g.Order = template.Order
+	g.Optional = template.Optional
+
+	for name, elem := range g.Elements {
+		if t, found := template.Elements[name]; found {
+			val := elem.Value
+			*elem = *t
+			elem.Value = val
+		}
+	}
+
+	for name, subgroup := range g.Subgroups {
+		if t, found := template.Subgroups[name]; found {
+			subgroup.applyTemplate(t)
+		}
+	}
+
+	for name, list := range g.Lists {
+		if t, found := template.Lists[name]; found {
+			list.Template = t.Template
+			list.MinItems = t.MinItems
+			list.MaxItems = t.MaxItems
+
+			list.ApplyTemplate()
+		}
+	}
+
+	for name, m := range g.Maps {
+		if t, found := template.Maps[name]; found {
+			m.Template = t.Template
+			m.KeyConstraints = t.KeyConstraints
+
+			m.ApplyTemplate()
+		}
+	}
+}
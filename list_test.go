@@ -0,0 +1,275 @@
+package setting_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testUpstream struct {
+	Host   string `setting:"host"`
+	Port   int64  `setting:"port"`
+	Weight uint64 `setting:"weight"`
+}
+
+type testProxy struct {
+	Name      string         `setting:"name"`
+	Upstreams []testUpstream `setting:"upstreams"`
+}
+
+func newTestProxyGroup(t *testing.T, proxy *testProxy) *setting.Group {
+	g, err := setting.FromStruct(proxy)
+
+	assert.NoError(t, err)
+
+	list := g.Lists["upstreams"]
+	list.MinItems = 1
+	list.MaxItems = 3
+	list.Template.Elements["port"].Constraints = []constraint.Constraint{
+		constraint.NewGreaterEqual(value.NewInt(1)),
+		constraint.NewLessEqual(value.NewInt(65535)),
+	}
+	list.ApplyTemplate()
+
+	return g
+}
+
+func TestListResize(t *testing.T) {
+	template := &setting.Group{
+		Elements: map[string]*setting.Element{"port": setting.NewElement(value.NewInt(80))},
+	}
+	l := setting.NewList(template, 0, 0)
+
+	l.Resize(2)
+	assert.Equal(t, 2, l.Len())
+
+	l.Items[0].Elements["port"].Value.(*value.Int).Set(8080)
+	l.Resize(3)
+	l.Resize(-1)
+	assert.Equal(t, 0, l.Len())
+
+	l.Resize(1)
+	assert.Equal(t, int64(80), l.Items[0].Elements["port"].Value.(value.Single).Value())
+	assert.Equal(t, int64(80), template.Elements["port"].Value.(value.Single).Value())
+}
+
+func TestListFromStruct(t *testing.T) {
+	proxy := &testProxy{Upstreams: []testUpstream{{Host: "a", Port: 1}}}
+	g := newTestProxyGroup(t, proxy)
+
+	assert.Equal(t, []string{"name", "upstreams"}, g.Names())
+	assert.Equal(t, 1, g.Lists["upstreams"].Len())
+
+	g.Lists["upstreams"].Resize(2)
+
+	assert.Len(t, proxy.Upstreams, 2)
+	assert.Equal(t, testUpstream{Host: "a", Port: 1}, proxy.Upstreams[0])
+
+	// items take constraints from the template
+	assert.Len(t, g.FindElement("upstreams[1]", "port").Constraints, 2)
+
+	assert.NoError(t, g.Set("upstreams[1].host", "b"))
+	assert.Equal(t, "b", proxy.Upstreams[1].Host)
+	assert.Equal(t, "name = \nupstreams[0].host = a\nupstreams[0].port = 1\n"+
+		"upstreams[0].weight = 0\nupstreams[1].host = b\nupstreams[1].port = 0\n"+
+		"upstreams[1].weight = 0\n", g.String())
+}
+
+func TestListFromStructResizeKeepsItems(t *testing.T) {
+	proxy := &testProxy{Upstreams: make([]testUpstream, 1, 4)}
+	g := newTestProxyGroup(t, proxy)
+	list := g.Lists["upstreams"]
+	first := list.Items[0]
+
+	list.Resize(3)
+
+	assert.Same(t, first, list.Items[0])
+	assert.NoError(t, g.Set("upstreams[2].host", "c"))
+
+	list.Resize(2)
+	list.Resize(3)
+
+	assert.Same(t, first, list.Items[0])
+	assert.Equal(t, testUpstream{}, proxy.Upstreams[2])
+
+	// the items are remade for a new backing array
+	proxy.Upstreams = []testUpstream{{Host: "x"}}
+	list.Resize(2)
+
+	assert.Equal(t, []testUpstream{{Host: "x"}, {}}, proxy.Upstreams)
+	assert.NoError(t, g.Set("upstreams[0].port", "5"))
+	assert.Equal(t, int64(5), proxy.Upstreams[0].Port)
+}
+
+func TestListValidate(t *testing.T) {
+	proxy := &testProxy{}
+	g := newTestProxyGroup(t, proxy)
+
+	err := g.Validate()
+
+	assert.True(t, errors.Is(err, setting.ErrItemCount))
+	assert.Equal(t, []string{"upstreams"}, setting.ErrorPath(err))
+	assert.Equal(t, "upstreams must have at least 1 items, not 0",
+		setting.NewCatalog().Render(err, "en"))
+
+	proxy.Upstreams = []testUpstream{{Port: 80}, {Port: 80}, {Port: 0}}
+	g = newTestProxyGroup(t, proxy)
+	err = g.Validate()
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, []string{"upstreams[2]", "port"}, setting.ErrorPath(err))
+	assert.Contains(t, err.Error(), "upstreams[2].port")
+
+	g.Lists["upstreams"].Resize(4)
+	err = g.Validate()
+
+	assert.Equal(t, "upstreams: has 4 items, expected at most 3", err.Error())
+}
+
+func TestListLoad(t *testing.T) {
+	proxy := &testProxy{Upstreams: []testUpstream{{Host: "old"}, {Host: "old"}}}
+	g := newTestProxyGroup(t, proxy)
+	doc := `{"name": "p", "upstreams": [
+		{"host": "a", "port": 80, "weight": 2},
+		{"host": "b", "port": "8080"}
+	]}`
+
+	assert.NoError(t, g.LoadJSON(strings.NewReader(doc)))
+	assert.Equal(t, &testProxy{Name: "p", Upstreams: []testUpstream{
+		{Host: "a", Port: 80, Weight: 2},
+		{Host: "b", Port: 8080},
+	}}, proxy)
+
+	port, err := g.GetInt("upstreams[1].port")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8080), port)
+
+	// items are replaced rather than merged
+	assert.NoError(t, g.Load(map[string]interface{}{
+		"upstreams": []interface{}{map[string]interface{}{"port": 1}},
+	}))
+	assert.Equal(t, []testUpstream{{Port: 1}}, proxy.Upstreams)
+}
+
+func TestListLoadErrors(t *testing.T) {
+	testCases := map[string]map[string]interface{}{
+		"not a list":     {"upstreams": map[string]interface{}{}},
+		"item not a map": {"upstreams": []interface{}{"x"}},
+		"item unknown":   {"upstreams": []interface{}{map[string]interface{}{"nope": 1}}},
+		"item bad value": {"upstreams": []interface{}{map[string]interface{}{"port": "x"}}},
+	}
+
+	for name, doc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			g := newTestProxyGroup(t, &testProxy{})
+
+			assert.Error(t, g.Load(doc))
+		})
+	}
+
+	g := newTestProxyGroup(t, &testProxy{})
+	err := g.Load(map[string]interface{}{
+		"upstreams": []interface{}{
+			map[string]interface{}{}, map[string]interface{}{"port": "x"}},
+	})
+
+	assert.Equal(t, []string{"upstreams[1]", "port"}, setting.ErrorPath(err))
+}
+
+func TestListLoadFlat(t *testing.T) {
+	proxy := &testProxy{}
+	g := newTestProxyGroup(t, proxy)
+
+	assert.NoError(t, g.LoadFlat(map[string]string{
+		"name":                "p",
+		"upstreams[1].port":   "8080",
+		"upstreams[0].host":   "a",
+		"upstreams[1].weight": "3",
+	}))
+	assert.Equal(t, &testProxy{Name: "p", Upstreams: []testUpstream{
+		{Host: "a"},
+		{Port: 8080, Weight: 3},
+	}}, proxy)
+
+	err := g.LoadFlat(map[string]string{"upstreams[3].port": "1"})
+
+	assert.True(t, errors.Is(err, setting.ErrItemCount))
+	assert.Equal(t, "upstreams: has 4 items, expected at most 3", err.Error())
+	assert.Len(t, proxy.Upstreams, 2)
+
+	err = g.LoadFlat(map[string]string{"upstreams[0].port": "x"})
+
+	assert.Equal(t, []string{"upstreams[0]", "port"}, setting.ErrorPath(err))
+}
+
+func TestListLoadFlatGrowth(t *testing.T) {
+	proxy := &testProxy{}
+	g, err := setting.FromStruct(proxy)
+
+	assert.NoError(t, err)
+
+	err = g.LoadFlat(map[string]string{"upstreams[1000000000].port": "1"})
+
+	assert.True(t, errors.Is(err, setting.ErrItemCount))
+	assert.Empty(t, proxy.Upstreams)
+
+	values := map[string]string{}
+	for i := 0; i < 12; i++ {
+		values[fmt.Sprintf("upstreams[%d].port", i)] = fmt.Sprint(i)
+	}
+
+	assert.NoError(t, g.LoadFlat(values))
+
+	if assert.Len(t, proxy.Upstreams, 12) {
+		assert.Equal(t, int64(11), proxy.Upstreams[11].Port)
+	}
+}
+
+func TestListGetNotFound(t *testing.T) {
+	g := newTestProxyGroup(t, &testProxy{Upstreams: []testUpstream{{}}})
+
+	_, err := g.Get("upstreams[1].port")
+
+	assert.True(t, errors.Is(err, setting.ErrNotFound))
+	assert.Len(t, g.Lists["upstreams"].Items, 1)
+
+	_, err = g.Get("upstreams.port")
+
+	assert.True(t, errors.Is(err, setting.ErrNotFound))
+}
+
+func TestListWalk(t *testing.T) {
+	g := newTestProxyGroup(t, &testProxy{Upstreams: []testUpstream{{}, {}}})
+	paths := []string{}
+
+	err := g.WalkGroups(func(path []string, sub *setting.Group) error {
+		paths = append(paths, strings.Join(path, "."))
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "upstreams[0]", "upstreams[1]"}, paths)
+}
+
+func TestGroupClone(t *testing.T) {
+	proxy := &testProxy{Name: "p", Upstreams: []testUpstream{{Host: "a"}}}
+	g := newTestProxyGroup(t, proxy)
+	clone := g.Clone()
+
+	assert.Equal(t, g.String(), clone.String())
+	assert.NoError(t, clone.Set("upstreams[0].host", "b"))
+	assert.NoError(t, clone.Set("name", "q"))
+
+	clone.Lists["upstreams"].Resize(2)
+
+	assert.Equal(t, &testProxy{Name: "p", Upstreams: []testUpstream{{Host: "a"}}}, proxy)
+	assert.Equal(t, 3, clone.Lists["upstreams"].MaxItems)
+}
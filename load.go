@@ -0,0 +1,386 @@
+package setting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// FileSuffix is added to the name of a sensitive element to give the key
+// for loading its value from a file, such as "password_file" for "password".
+// The file contents are used as the value, without trailing newlines.
+const FileSuffix = "_file"
+
+// Load sets element values from a raw document, such as one decoded from
+// JSON or YAML. Nested maps are loaded into subgroups and map entries, lists of
+// maps are loaded into list items, and other lists are loaded into slice
+// elements. Existing list items and map entries are replaced. An optional
+// subgroup is made present if given, or absent if given as null. Strings are
+// parsed using Value.Parse. Values given by alias are moved to the new path,
+// and deprecation warnings for aliases and deprecated elements are collected
+// in Warnings. Values are not validated. If loading fails, values that were
+// set before the failure are kept, so the group can be partly loaded. Use
+// Store.Load to load all values or none.
+// Returns a non-nil error for an unknown key, a value that cannot be set, or
+// a value that is given by both old and new paths.
+func (g *Group) Load(doc map[string]interface{}) error {
+	return g.trackChanges(func() error {
+		warnings := []*DeprecationWarning{}
+
+		err := g.load(copyDocument(doc), []string{}, &warnings)
+
+		g.Warnings = warnings
+
+		g.syncMaps()
+
+		return err
+	})
+}
+
+// LoadJSON decodes a JSON object and loads it, as in Load.
+func (g *Group) LoadJSON(r io.Reader) error {
+	doc, err := decodeJSON(r)
+	if err != nil {
+		return err
+	}
+
+	return g.Load(doc)
+}
+
+// LoadFlat sets element values from strings by dotted path (see Get), such as
+// values given by command-line flags. An indexed path such as
+// "upstreams[2].port" adds list items as needed, one past the last item at
+// most and up to the maximum number of items, and a path such as
+// "tenants.acme.rate_limit" adds map entries as needed. Optional subgroups in
+// the path are made present, and paths given by alias are resolved to the new
+// path, with deprecation warnings collected in Warnings. Strings are parsed
+// using Value.Parse. Values are not validated.
+// Returns a non-nil error for an unknown path or a value that cannot be set.
+func (g *Group) LoadFlat(values map[string]string) error {
+	return g.trackChanges(func() error {
+		defer g.syncMaps()
+
+		return g.loadFlat(values)
+	})
+}
+
+func (g *Group) loadFlat(values map[string]string) error {
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return lessPath(paths[i], paths[j])
+	})
+
+	warnings := []*DeprecationWarning{}
+	defer func() { g.Warnings = warnings }()
+
+	for _, path := range paths {
+		elem, names, index, err := g.lookup(path, true, &warnings)
+		if err != nil {
+			return err
+		}
+
+		if index == noIndex {
+			err = elem.Value.Parse(values[path])
+		} else {
+			err = parseIndex(elem.Value, names, index, values[path])
+		}
+
+		if err != nil {
+			return annotateError(err, names, elem.Sensitive)
+		}
+	}
+
+	return nil
+}
+
+func decodeJSON(r io.Reader) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+
+	d := json.NewDecoder(r)
+	d.UseNumber()
+
+	if err := d.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	return doc, nil
+}
+
+func (g *Group) load(
+	doc map[string]interface{},
+	path []string,
+	warnings *[]*DeprecationWarning,
+) error {
+	if err := g.resolveAliases(doc, path, warnings); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw := doc[key]
+		keyPath := appendPath(path, key)
+
+		if elem, found := g.Elements[key]; found {
+			if err := setRaw(elem.Value, raw); err != nil {
+				return annotateError(err, keyPath, elem.Sensitive)
+			}
+
+			if elem.Deprecated != nil {
+				*warnings = append(*warnings, elem.Deprecated.warning(keyPath))
+			}
+
+			continue
+		}
+
+		if subgroup, found := g.Subgroups[key]; found {
+			if raw == nil && subgroup.Optional {
+				subgroup.SetPresent(false)
+
+				continue
+			}
+
+			subgroup.SetPresent(true)
+
+			subdoc, ok := raw.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s: expected an object for subgroup, got %T",
+					strings.Join(keyPath, "."), raw)
+			}
+
+			if err := subgroup.load(subdoc, keyPath, warnings); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if list, found := g.Lists[key]; found {
+			if err := list.load(raw, key, path, warnings); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if m, found := g.Maps[key]; found {
+			if err := m.load(raw, keyPath, warnings); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := g.loadFile(doc, key, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// load replaces the list items with new items loaded from a raw list of maps.
+func (l *List) load(
+	raw interface{},
+	name string,
+	path []string,
+	warnings *[]*DeprecationWarning,
+) error {
+	rawItems, ok := raw.([]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected a list for list items, got %T",
+			strings.Join(appendPath(path, name), "."), raw)
+	}
+
+	l.Resize(0)
+	l.Resize(len(rawItems))
+
+	for i, rawItem := range rawItems {
+		itemPath := appendPath(path, itemName(name, i))
+
+		itemDoc, ok := rawItem.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object for list item, got %T",
+				strings.Join(itemPath, "."), rawItem)
+		}
+
+		if err := l.Items[i].load(itemDoc, itemPath, warnings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// load replaces the map entries with new entries loaded from a raw map.
+func (m *Map) load(raw interface{}, path []string, warnings *[]*DeprecationWarning) error {
+	rawEntries, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected an object for map entries, got %T",
+			strings.Join(path, "."), raw)
+	}
+
+	m.Clear()
+
+	keys := make([]string, 0, len(rawEntries))
+	for key := range rawEntries {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		entryPath := appendPath(path, key)
+
+		entryDoc, ok := rawEntries[key].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object for map entry, got %T",
+				strings.Join(entryPath, "."), rawEntries[key])
+		}
+
+		if err := m.Add(key).load(entryDoc, entryPath, warnings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveAliases moves values given by alias to the new path.
+func (g *Group) resolveAliases(
+	doc map[string]interface{},
+	path []string,
+	warnings *[]*DeprecationWarning,
+) error {
+	for _, alias := range g.Aliases {
+		raw, found := documentGet(doc, alias.Old)
+		if !found {
+			continue
+		}
+
+		if _, found := documentGet(doc, alias.New); found {
+			return fmt.Errorf("%s: cannot set both %s and %s",
+				strings.Join(appendPath(path, alias.New...), "."),
+				strings.Join(alias.Old, "."), strings.Join(alias.New, "."))
+		}
+
+		documentDelete(doc, alias.Old)
+
+		if !documentSet(doc, alias.New, raw) {
+			return fmt.Errorf("%s: cannot move value from alias %s",
+				strings.Join(appendPath(path, alias.New...), "."), strings.Join(alias.Old, "."))
+		}
+
+		*warnings = append(*warnings, alias.warning(path))
+	}
+
+	return nil
+}
+
+// loadFile loads a sensitive element value from a file, given a key with FileSuffix.
+func (g *Group) loadFile(doc map[string]interface{}, key string, path []string) error {
+	name := strings.TrimSuffix(key, FileSuffix)
+	elem, found := g.Elements[name]
+
+	if name == key || !found || !elem.Sensitive {
+		return fmt.Errorf("%s: unknown setting", strings.Join(appendPath(path, key), "."))
+	}
+
+	elemPath := appendPath(path, name)
+
+	if _, found := doc[name]; found {
+		return fmt.Errorf("%s: cannot set both %s and %s",
+			strings.Join(elemPath, "."), name, key)
+	}
+
+	filePath, ok := doc[key].(string)
+	if !ok {
+		return fmt.Errorf("%s: expected a file path string, got %T",
+			strings.Join(appendPath(path, key), "."), doc[key])
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read file: %w", strings.Join(elemPath, "."), err)
+	}
+
+	if err = elem.Value.Parse(strings.TrimRight(string(data), "\r\n")); err != nil {
+		return annotateError(err, elemPath, elem.Sensitive)
+	}
+
+	return nil
+}
+
+// setRaw sets a value from a raw document value.
+func setRaw(v value.Value, raw interface{}) error {
+	slice, isSlice := v.(value.Slice)
+	items, isList := raw.([]interface{})
+
+	if !isSlice || !isList {
+		if isList {
+			return fmt.Errorf("expected a single value, got a list")
+		}
+
+		str, err := rawString(raw)
+		if err != nil {
+			return err
+		}
+
+		return v.Parse(str)
+	}
+
+	sliceVal := reflect.ValueOf(slice.SlicePointer()).Elem()
+	newVals := reflect.MakeSlice(sliceVal.Type(), len(items), len(items))
+
+	for i, item := range items {
+		str, err := rawString(item)
+		if err != nil {
+			return err
+		}
+
+		single := value.NewSingle(v.Type())
+		if err = single.Parse(str); err != nil {
+			return err
+		}
+
+		newVals.Index(i).Set(reflect.ValueOf(single.Value()))
+	}
+
+	sliceVal.Set(newVals)
+
+	return nil
+}
+
+// rawString converts a raw document value to a string for parsing.
+func rawString(raw interface{}) (string, error) {
+	switch raw := raw.(type) {
+	case string:
+		return raw, nil
+	case json.Number:
+		return raw.String(), nil
+	case bool:
+		return strconv.FormatBool(raw), nil
+	case float64:
+		return strconv.FormatFloat(raw, 'f', -1, 64), nil
+	case int, int64, uint64:
+		return fmt.Sprint(raw), nil
+	}
+
+	return "", fmt.Errorf("unsupported raw value type %T", raw)
+}
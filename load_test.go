@@ -0,0 +1,105 @@
+package setting_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDB struct {
+	User     string `setting:"user"`
+	Password string `setting:"password" sensitive:"true"`
+}
+
+type testApp struct {
+	Port    int64    `setting:"port"`
+	Ratio   float64  `setting:"ratio"`
+	Debug   bool     `setting:"debug"`
+	Regions []string `setting:"regions"`
+	Weights []uint64 `setting:"weights"`
+	DB      testDB   `setting:"db"`
+}
+
+func TestGroupLoadJSON(t *testing.T) {
+	app := &testApp{}
+	g, err := setting.FromStruct(app)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	doc := `{
+		"port": 8080, "ratio": 0.5, "debug": true,
+		"regions": ["us", "eu,west"], "weights": "1, 2",
+		"db": {"user": "app", "password": "secret"}
+	}`
+
+	assert.NoError(t, g.LoadJSON(strings.NewReader(doc)))
+	assert.Equal(t, &testApp{
+		Port: 8080, Ratio: 0.5, Debug: true,
+		Regions: []string{"us", "eu,west"},
+		Weights: []uint64{1, 2},
+		DB:      testDB{User: "app", Password: "secret"},
+	}, app)
+}
+
+func TestGroupLoadErrors(t *testing.T) {
+	g, _ := setting.FromStruct(&testApp{})
+
+	testCases := map[string]map[string]interface{}{
+		"unknown":         {"nope": 1},
+		"unknown in sub":  {"db": map[string]interface{}{"nope": 1}},
+		"subgroup":        {"db": "x"},
+		"list for single": {"port": []interface{}{1}},
+		"raw type":        {"port": map[string]interface{}{}},
+		"list item":       {"weights": []interface{}{-1}},
+		"not sensitive":   {"db": map[string]interface{}{"user_file": "x"}},
+	}
+
+	for name, doc := range testCases {
+		assert.Error(t, g.Load(doc), name)
+	}
+
+	err := g.Load(map[string]interface{}{"port": "abc"})
+
+	assert.True(t, errors.Is(err, value.ErrParse))
+	assert.Equal(t, []string{"port"}, setting.ErrorPath(err))
+	assert.Error(t, g.LoadJSON(strings.NewReader("[")))
+}
+
+func TestGroupLoadSensitiveFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	secretPath := filepath.Join(dir, "password")
+
+	assert.NoError(t, ioutil.WriteFile(secretPath, []byte("s3cret\n"), 0600))
+
+	app := &testApp{}
+	g, _ := setting.FromStruct(app)
+	db := map[string]interface{}{"password_file": secretPath}
+
+	assert.True(t, g.FindElement("db", "password").Sensitive)
+	assert.False(t, g.FindElement("db", "user").Sensitive)
+	assert.NoError(t, g.Load(map[string]interface{}{"db": db}))
+	assert.Equal(t, "s3cret", app.DB.Password)
+
+	db["password"] = "other"
+
+	assert.Error(t, g.Load(map[string]interface{}{"db": db}))
+
+	missing := map[string]interface{}{"password_file": filepath.Join(dir, "missing")}
+
+	assert.Error(t, g.Load(map[string]interface{}{"db": missing}))
+}
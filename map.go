@@ -0,0 +1,216 @@
+package setting
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// Map is a subgroup map with arbitrary keys, such as per-tenant settings.
+// Each entry is a group with the same elements, constraints and metadata as
+// the template, and is visited with a path such as "tenants.acme".
+// Changes to the template apply to new entries, or to existing entries by
+// calling ApplyTemplate.
+type Map struct {
+	// Template is the group schema for each entry. It is not validated itself.
+	Template *Group
+	// Entries are the map entries, by key
+	Entries map[string]*Group
+	// KeyConstraints restrict the keys, such as a constraint.OneOf or constraint.Pattern
+	KeyConstraints []constraint.Constraint
+
+	// add, remove and sync replace the default behavior, for maps made by FromStruct
+	add    func(key string) *Group
+	remove func(key string)
+	sync   func()
+}
+
+// NewMap makes a new map with no entries.
+func NewMap(template *Group, keyConstraints ...constraint.Constraint) *Map {
+	return &Map{
+		Template:       template,
+		Entries:        map[string]*Group{},
+		KeyConstraints: keyConstraints,
+	}
+}
+
+// Keys returns the entry keys in sorted order.
+func (m *Map) Keys() []string {
+	keys := make([]string, 0, len(m.Entries))
+	for key := range m.Entries {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Add returns the entry for the key, adding a new entry cloned from the
+// template if needed.
+func (m *Map) Add(key string) *Group {
+	if entry, found := m.Entries[key]; found {
+		return entry
+	}
+
+	var entry *Group
+
+	if m.add != nil {
+		entry = m.add(key)
+	} else {
+		entry = m.Template.Clone()
+	}
+
+	m.Entries[key] = entry
+
+	return entry
+}
+
+// Remove removes the entry for the key, if any.
+func (m *Map) Remove(key string) {
+	if _, found := m.Entries[key]; !found {
+		return
+	}
+
+	if m.remove != nil {
+		m.remove(key)
+	}
+
+	delete(m.Entries, key)
+}
+
+// Clear removes all entries.
+func (m *Map) Clear() {
+	for _, key := range m.Keys() {
+		m.Remove(key)
+	}
+}
+
+// ApplyTemplate updates existing entries after a change to the template, copying
+// everything but element values, such as constraints and metadata.
+func (m *Map) ApplyTemplate() {
+	for _, entry := range m.Entries {
+		entry.applyTemplate(m.Template)
+	}
+}
+
+// checkKeys checks each key against the key constraints.
+func (m *Map) checkKeys(path []string) error {
+	for _, key := range m.Keys() {
+		for _, c := range m.KeyConstraints {
+			if err := c.Check(value.NewString(key)); err != nil {
+				return annotateError(err, appendPath(path, key), false)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MapNames returns the map names in sorted order.
+func (g *Group) MapNames() []string {
+	names := make([]string, 0, len(g.Maps))
+	for name := range g.Maps {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// syncMaps copies entry values into any maps of struct values made by
+// FromStruct, including those in subgroups, list items and map entries.
+func (g *Group) syncMaps() {
+	_ = g.WalkGroups(func(path []string, group *Group) error {
+		for _, m := range group.Maps {
+			if m.sync != nil {
+				m.sync()
+			}
+		}
+
+		return nil
+	})
+}
+
+// mapFromStruct makes a map from a Go map with string keys and struct
+// (or struct pointer) values. For struct values, each entry points to a copy
+// that is written back to the Go map when syncing.
+func mapFromStruct(rv reflect.Value, path []string) (*Map, error) {
+	mapType := rv.Type()
+	isPtr := mapType.Elem().Kind() == reflect.Ptr
+	structType := mapType.Elem()
+
+	if isPtr {
+		structType = structType.Elem()
+	}
+
+	template, err := groupFromStruct(reflect.New(structType).Elem(), appendPath(path, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	m := NewMap(template)
+	ptrs := map[string]reflect.Value{}
+
+	makeEntry := func(key string, ptr reflect.Value) *Group {
+		// the struct type was already checked when making the template
+		entry, _ := groupFromStruct(ptr.Elem(), path)
+		entry.applyTemplate(m.Template)
+
+		ptrs[key] = ptr
+
+		return entry
+	}
+
+	mapKey := func(key string) reflect.Value {
+		return reflect.ValueOf(key).Convert(mapType.Key())
+	}
+
+	for _, key := range rv.MapKeys() {
+		ptr := rv.MapIndex(key)
+
+		if !isPtr {
+			ptr = reflect.New(structType)
+			ptr.Elem().Set(rv.MapIndex(key))
+		} else if ptr.IsNil() {
+			ptr = reflect.New(structType)
+			rv.SetMapIndex(key, ptr)
+		}
+
+		m.Entries[key.String()] = makeEntry(key.String(), ptr)
+	}
+
+	m.add = func(key string) *Group {
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(mapType))
+		}
+
+		ptr := reflect.New(structType)
+
+		if isPtr {
+			rv.SetMapIndex(mapKey(key), ptr)
+		} else {
+			rv.SetMapIndex(mapKey(key), ptr.Elem())
+		}
+
+		return makeEntry(key, ptr)
+	}
+
+	m.remove = func(key string) {
+		rv.SetMapIndex(mapKey(key), reflect.Value{})
+		delete(ptrs, key)
+	}
+
+	if !isPtr {
+		m.sync = func() {
+			for key, ptr := range ptrs {
+				rv.SetMapIndex(mapKey(key), ptr.Elem())
+			}
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,180 @@
+package setting_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTenant struct {
+	RateLimit int64  `setting:"rate_limit"`
+	Region    string `setting:"region"`
+}
+
+type testTenants struct {
+	Tenants map[string]testTenant  `setting:"tenants"`
+	Regions map[string]*testTenant `setting:"regions"`
+}
+
+func newTestTenantsGroup(t *testing.T, tenants *testTenants) *setting.Group {
+	g, err := setting.FromStruct(tenants)
+
+	assert.NoError(t, err)
+
+	m := g.Maps["tenants"]
+	m.KeyConstraints = []constraint.Constraint{
+		constraint.NewPattern(regexp.MustCompile(`^[a-z]+$`)),
+	}
+	m.Template.Elements["rate_limit"].Constraints = []constraint.Constraint{
+		constraint.NewGreater(value.NewInt(0)),
+	}
+	m.ApplyTemplate()
+
+	return g
+}
+
+func TestMapAddRemove(t *testing.T) {
+	template := &setting.Group{
+		Elements: map[string]*setting.Element{"limit": setting.NewElement(value.NewInt(10))},
+	}
+	m := setting.NewMap(template)
+
+	acme := m.Add("acme")
+	acme.Elements["limit"].Value.(*value.Int).Set(20)
+
+	assert.Same(t, acme, m.Add("acme"))
+
+	m.Add("globex")
+	assert.Equal(t, []string{"acme", "globex"}, m.Keys())
+
+	m.Remove("acme")
+	m.Remove("missing")
+	assert.Equal(t, []string{"globex"}, m.Keys())
+
+	m.Clear()
+	assert.Empty(t, m.Keys())
+	assert.Equal(t, int64(10), template.Elements["limit"].Value.(value.Single).Value())
+}
+
+func TestMapFromStruct(t *testing.T) {
+	tenants := &testTenants{
+		Tenants: map[string]testTenant{"acme": {RateLimit: 5}},
+		Regions: map[string]*testTenant{"eu": nil},
+	}
+	g := newTestTenantsGroup(t, tenants)
+
+	assert.Equal(t, []string{"acme"}, g.Maps["tenants"].Keys())
+	assert.Equal(t, &testTenant{}, tenants.Regions["eu"])
+
+	assert.NoError(t, g.Set("tenants.acme.rate_limit", "7"))
+	assert.NoError(t, g.Set("regions.eu.region", "eu-west"))
+	assert.Equal(t, testTenant{RateLimit: 7}, tenants.Tenants["acme"])
+	assert.Equal(t, &testTenant{Region: "eu-west"}, tenants.Regions["eu"])
+
+	g.Maps["tenants"].Add("globex")
+	g.Maps["regions"].Add("us")
+	g.Maps["tenants"].Remove("acme")
+
+	assert.Equal(t, map[string]testTenant{"globex": {}}, tenants.Tenants)
+	assert.Equal(t, &testTenant{}, tenants.Regions["us"])
+
+	assert.Equal(t, "tenants.globex.rate_limit = 0\ntenants.globex.region = \n"+
+		"regions.eu.rate_limit = 0\nregions.eu.region = eu-west\n"+
+		"regions.us.rate_limit = 0\nregions.us.region = \n", g.String())
+}
+
+func TestMapValidate(t *testing.T) {
+	tenants := &testTenants{Tenants: map[string]testTenant{"Acme": {RateLimit: 5}}}
+	g := newTestTenantsGroup(t, tenants)
+
+	err := g.Validate()
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, []string{"tenants", "Acme"}, setting.ErrorPath(err))
+
+	tenants.Tenants = map[string]testTenant{"acme": {}}
+	g = newTestTenantsGroup(t, tenants)
+	err = g.Validate()
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, []string{"tenants", "acme", "rate_limit"}, setting.ErrorPath(err))
+	assert.NotNil(t, g.FindElement("tenants", "acme", "rate_limit"))
+	assert.Nil(t, g.FindElement("tenants", "globex", "rate_limit"))
+}
+
+func TestMapLoad(t *testing.T) {
+	tenants := &testTenants{Tenants: map[string]testTenant{"old": {}}}
+	g := newTestTenantsGroup(t, tenants)
+	doc := `{
+		"tenants": {"acme": {"rate_limit": 5}, "globex": {"region": "eu"}},
+		"regions": {"us": {"rate_limit": 1}}
+	}`
+
+	assert.NoError(t, g.LoadJSON(strings.NewReader(doc)))
+	assert.Equal(t, &testTenants{
+		Tenants: map[string]testTenant{"acme": {RateLimit: 5}, "globex": {Region: "eu"}},
+		Regions: map[string]*testTenant{"us": {RateLimit: 1}},
+	}, tenants)
+
+	limit, err := g.GetInt("tenants.acme.rate_limit")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), limit)
+
+	assert.Error(t, g.Load(map[string]interface{}{"tenants": "x"}))
+	assert.Error(t, g.Load(map[string]interface{}{
+		"tenants": map[string]interface{}{"acme": 1}}))
+
+	err = g.Load(map[string]interface{}{
+		"tenants": map[string]interface{}{"acme": map[string]interface{}{"rate_limit": "x"}}})
+
+	assert.Equal(t, []string{"tenants", "acme", "rate_limit"}, setting.ErrorPath(err))
+}
+
+func TestMapLoadFlat(t *testing.T) {
+	tenants := &testTenants{}
+	g := newTestTenantsGroup(t, tenants)
+
+	assert.NoError(t, g.LoadFlat(map[string]string{
+		"tenants.acme.rate_limit": "5",
+		"tenants.a\\.b.region":    "eu",
+	}))
+	assert.Equal(t, map[string]testTenant{
+		"acme": {RateLimit: 5},
+		"a.b":  {Region: "eu"},
+	}, tenants.Tenants)
+
+	_, err := g.Get("tenants.globex.rate_limit")
+
+	assert.True(t, errors.Is(err, setting.ErrNotFound))
+	assert.Equal(t, "tenants.globex: not found", err.Error())
+}
+
+func TestMapLoadEnv(t *testing.T) {
+	tenants := &testTenants{Tenants: map[string]testTenant{"acme": {}}}
+	g := newTestTenantsGroup(t, tenants)
+
+	assert.NoError(t, g.LoadEnv("APP", []string{
+		"APP_TENANTS_ACME_RATE_LIMIT=3",
+		"APP_TENANTS_GLOBEX_RATE_LIMIT=4",
+	}))
+	assert.Equal(t, map[string]testTenant{"acme": {RateLimit: 3}}, tenants.Tenants)
+}
+
+func TestMapClone(t *testing.T) {
+	tenants := &testTenants{Tenants: map[string]testTenant{"acme": {RateLimit: 5}}}
+	g := newTestTenantsGroup(t, tenants)
+	clone := g.Clone()
+
+	assert.NoError(t, clone.Set("tenants.acme.rate_limit", "6"))
+	clone.Maps["tenants"].Add("globex")
+
+	assert.Equal(t, map[string]testTenant{"acme": {RateLimit: 5}}, tenants.Tenants)
+	assert.Len(t, clone.Maps["tenants"].KeyConstraints, 1)
+}
@@ -0,0 +1,281 @@
+package setting
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// DefaultLocale is the locale of the built-in message templates.
+const DefaultLocale = "en"
+
+// KeyNoPath is the message key for the text used as {path} when an error
+// has no path.
+const KeyNoPath = "no_path"
+
+// Messages maps message keys to message templates. A key is either a
+// constraint type string (for a violation) or an error code.
+//
+// Templates can use these placeholders, which are replaced when rendering:
+// {path}, {value}, {param}, {constraint}, {reason}, {error},
+// {expected}, {actual}, {input} and {type}. A placeholder without a value
+// for the error is replaced by an empty string, together with a ": "
+// separator just before it.
+type Messages map[string]string
+
+// Catalog holds message templates by locale.
+type Catalog struct {
+	// Locales maps a locale (such as "en" or "pt-BR") to message templates
+	Locales map[string]Messages
+	// Fallback is the locale used when a template is missing
+	Fallback string
+}
+
+// LocalizedError is a structured error together with a message rendered for a locale.
+type LocalizedError struct {
+	Err     error
+	Locale  string
+	Message string
+}
+
+// DefaultMessages returns the built-in English message templates.
+func DefaultMessages() Messages {
+	return Messages{
+		constraint.GreaterStr:      "{path} must be greater than {param}",
+		constraint.GreaterEqualStr: "{path} must be at least {param}",
+		constraint.LessStr:         "{path} must be less than {param}",
+		constraint.LessEqualStr:    "{path} must be at most {param}",
+		constraint.OneOfStr:        "{path} must be one of {param}",
+		constraint.NoneOfStr:       "{path} must not be {value}",
+		constraint.MinLenStr:       "{path} must have a length of at least {param}",
+		constraint.MaxLenStr:       "{path} must have a length of at most {param}",
+		constraint.EachStr:         "{path} must satisfy {constraint} ({reason})",
+		constraint.UniqueStr:       "{path} must not have duplicates ({value} is {reason})",
+		constraint.SortedStr:       "{path} must be sorted in {param} order ({reason})",
+		constraint.ContainsStr:     "{path} must contain {param}",
+		constraint.SumLessEqualStr: "{path} must have a sum of at most {param} ({reason})",
+		constraint.MultipleOfStr:   "{path} must be a multiple of {param}",
+		constraint.FuncStr:         "{path} must satisfy {constraint}: {error}",
+		constraint.AllOfStr:        "{path} must satisfy {constraint}",
+		constraint.AnyOfStr:        "{path} must satisfy {constraint}",
+		constraint.NotStr:          "{path} must satisfy {constraint}",
+		constraint.PatternStr:      "{path} must match {param}",
+
+		value.CodeTypeMismatch:                 "{path} must have type {expected}, not {actual}",
+		value.CodeParse:                        "{path} cannot parse {input} as {type}",
+		constraint.CodeNotApplicable:           "{path} cannot be checked with {constraint}",
+		constraint.CodeIncompatibleConstraints: "{path} has incompatible constraints {constraint}",
+		CodeNotFound:                           "{path} is not a known setting",
+		CodeItemCount:                          "{path} must have {expected} items, not {actual}",
+		KeyNoPath:                              "value",
+	}
+}
+
+// NewCatalog makes a new catalog with the default messages for the default locale.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		Locales:  map[string]Messages{DefaultLocale: DefaultMessages()},
+		Fallback: DefaultLocale,
+	}
+}
+
+// Set adds the given message templates for the locale, replacing any that
+// have the same key.
+func (c *Catalog) Set(locale string, msgs Messages) {
+	existing, found := c.Locales[locale]
+	if !found {
+		existing = Messages{}
+		c.Locales[locale] = existing
+	}
+
+	for key, template := range msgs {
+		existing[key] = template
+	}
+}
+
+// Template looks up a message template. The locale is tried first, then its base
+// language (e.g. "pt" for "pt-BR"), then the fallback locale.
+// Returns false if the template is not found.
+func (c *Catalog) Template(locale, key string) (string, bool) {
+	return lookupTemplate(c.Locales, c.Fallback, locale, key)
+}
+
+// Render makes a message for the given error using a template for the locale.
+// Returns the error string if there is no template for the error.
+func (c *Catalog) Render(err error, locale string) string {
+	template, found := c.Template(locale, MessageKey(err))
+	if !found {
+		return err.Error()
+	}
+
+	return c.renderTemplate(template, err, locale)
+}
+
+// RenderError makes a message for the given error, like Catalog.Render, but
+// first checks for a template in the messages of the element at the error path.
+// Element messages under the empty locale apply to all locales.
+func (g *Group) RenderError(err error, c *Catalog, locale string) string {
+	if path := ErrorPath(err); len(path) > 0 {
+		if elem := g.FindElement(path...); elem != nil && elem.Messages != nil {
+			template, found := lookupTemplate(elem.Messages, "", locale, MessageKey(err))
+			if found {
+				return c.renderTemplate(template, err, locale)
+			}
+		}
+	}
+
+	return c.Render(err, locale)
+}
+
+// ValidateLocale validates the group as in Validate, and renders a message
+// for any error using the given catalog and locale.
+// Returns a non-nil *LocalizedError in case of failure.
+func (g *Group) ValidateLocale(c *Catalog, locale string) error {
+	err := g.Validate()
+	if err == nil {
+		return nil
+	}
+
+	return &LocalizedError{
+		Err:     err,
+		Locale:  locale,
+		Message: g.RenderError(err, c, locale),
+	}
+}
+
+// Error returns the rendered message.
+func (e *LocalizedError) Error() string { return e.Message }
+
+// Unwrap returns the structured error.
+func (e *LocalizedError) Unwrap() error { return e.Err }
+
+// MessageKey returns the message key for the given error. This is the
+// constraint type string for a violation, and otherwise the error code.
+func MessageKey(err error) string {
+	var violation *constraint.ViolationError
+
+	if errors.As(err, &violation) {
+		return violation.Constraint.Type().String()
+	}
+
+	return ErrorCode(err)
+}
+
+func lookupTemplate(locales map[string]Messages, fallback, locale, key string) (string, bool) {
+	candidates := []string{locale}
+
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		candidates = append(candidates, locale[:i])
+	}
+
+	candidates = append(candidates, fallback)
+
+	for _, candidate := range candidates {
+		if template, found := locales[candidate][key]; found {
+			return template, true
+		}
+	}
+
+	return "", false
+}
+
+// placeholders are the names that templates can use.
+var placeholders = []string{
+	"path", "value", "param", "constraint", "reason", "error", "expected", "actual", "input", "type"}
+
+// renderTemplate fills in the template placeholders. The catalog gives the
+// {path} text for an error without a path.
+func (c *Catalog) renderTemplate(template string, err error, locale string) string {
+	noPath, _ := c.Template(locale, KeyNoPath)
+	fields := map[string]string{"path": noPath}
+
+	if path := ErrorPath(err); len(path) > 0 {
+		fields["path"] = strings.Join(path, ".")
+	}
+
+	var (
+		violation     *constraint.ViolationError
+		notApplicable *constraint.NotApplicableError
+		incompatible  *constraint.IncompatibleConstraintsError
+		typeMismatch  *value.TypeMismatchError
+		parse         *value.ParseError
+		itemCount     *ItemCountError
+	)
+
+	switch {
+	case errors.As(err, &violation):
+		fields["value"] = formatRedacted(violation.Value, violation.Redacted)
+		fields["constraint"] = violation.Constraint.String()
+		if !violation.Redacted {
+			fields["reason"] = violation.Reason
+		}
+
+		if param := violation.Constraint.Param(); param != nil {
+			fields["param"] = constraint.FormatValue(param)
+		}
+
+		if violation.Err != nil && !violation.Redacted {
+			fields["error"] = violation.Err.Error()
+		}
+	case errors.As(err, &notApplicable):
+		fields["value"] = formatRedacted(notApplicable.Value, notApplicable.Redacted)
+		fields["constraint"] = notApplicable.Constraint.String()
+	case errors.As(err, &incompatible):
+		strs := make([]string, len(incompatible.Constraints))
+		for i, c := range incompatible.Constraints {
+			strs[i] = c.String()
+		}
+
+		fields["constraint"] = strings.Join(strs, ", ")
+	case errors.As(err, &typeMismatch):
+		fields["expected"] = typeMismatch.Expected.String()
+		fields["actual"] = typeMismatch.Actual.String()
+
+		if typeMismatch.ExpectedSlice {
+			fields["expected"] = "[]" + fields["expected"]
+		}
+
+		if typeMismatch.ActualSlice {
+			fields["actual"] = "[]" + fields["actual"]
+		}
+	case errors.As(err, &parse):
+		fields["input"] = parse.Input
+		fields["type"] = parse.Type.String()
+		fields["error"] = parse.Err.Error()
+
+		if parse.Redacted {
+			fields["input"] = value.Redacted
+			fields["error"] = ""
+		}
+
+		if parse.Slice {
+			fields["type"] = "[]" + fields["type"]
+		}
+	case errors.As(err, &itemCount):
+		fields["expected"] = itemCount.Expected()
+		fields["actual"] = strconv.Itoa(itemCount.Count)
+	}
+
+	// placeholders without a value are removed, along with their separator
+	replacements := []string{}
+	for _, name := range placeholders {
+		if fields[name] == "" {
+			replacements = append(replacements, ": {"+name+"}", "")
+		}
+
+		replacements = append(replacements, "{"+name+"}", fields[name])
+	}
+
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+func formatRedacted(v value.Value, redacted bool) string {
+	if redacted {
+		return value.Redacted
+	}
+
+	return constraint.FormatValue(v)
+}
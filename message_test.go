@@ -0,0 +1,120 @@
+package setting_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultMessagesCoverAllTypes(t *testing.T) {
+	msgs := setting.DefaultMessages()
+
+	for _, typ := range constraint.AllTypes() {
+		assert.Contains(t, msgs, typ.String())
+	}
+}
+
+func TestCatalogRender(t *testing.T) {
+	c := setting.NewCatalog()
+	err := constraint.NewLessEqual(value.NewInt(10)).Check(value.NewInt(11))
+
+	assert.Equal(t, "lessEqual", setting.MessageKey(err))
+	assert.Equal(t, "value must be at most 10", c.Render(err, "en"))
+	assert.Equal(t, "value must be at most 10", c.Render(err, "de"))
+
+	c.Set("de", setting.Messages{"lessEqual": "{path} darf höchstens {param} sein"})
+
+	assert.Equal(t, "value darf höchstens 10 sein", c.Render(err, "de"))
+	assert.Equal(t, "value darf höchstens 10 sein", c.Render(err, "de-AT"))
+
+	c.Set("de", setting.Messages{setting.KeyNoPath: "Wert"})
+
+	assert.Equal(t, "Wert darf höchstens 10 sein", c.Render(err, "de"))
+	assert.Equal(t, "value must be at most 10", c.Render(err, "en"))
+
+	plain := errors.New("plain")
+
+	assert.Equal(t, "plain", c.Render(plain, "en"))
+}
+
+func TestCatalogRenderOtherErrors(t *testing.T) {
+	c := setting.NewCatalog()
+
+	assert.Equal(t, "value must have type int64, not string",
+		c.Render(value.CheckType(value.TypeInt, value.TypeString), "en"))
+	assert.Equal(t, `value cannot parse abc as []uint64`,
+		c.Render(value.NewUIntSlice().Parse("abc"), "en"))
+	assert.Equal(t, "value cannot be checked with unique(x)",
+		c.Render(constraint.NewUnique().Check(value.NewInt(0)), "en"))
+}
+
+func TestCatalogRenderMissingPlaceholders(t *testing.T) {
+	c := setting.NewCatalog()
+	err := constraint.NewLessEqual(value.NewInt(10)).Check(value.NewInt(11))
+
+	c.Set("en", setting.Messages{"lessEqual": "{path} must be at most {param}{error}{input}, {other}"})
+
+	assert.Equal(t, "value must be at most 10, {other}", c.Render(err, "en"))
+
+	c.Set("en", setting.Messages{value.CodeTypeMismatch: "{path}: expected {expected} ({param})"})
+
+	assert.Equal(t, "value: expected int64 ()",
+		c.Render(value.CheckType(value.TypeInt, value.TypeString), "en"))
+}
+
+func TestCatalogRenderRedactedFunc(t *testing.T) {
+	fn := constraint.NewFunc("even", "an even number", func(v value.Value) error {
+		return errors.New("odd")
+	})
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"pin": {Value: value.NewInt(3), Sensitive: true, Constraints: []constraint.Constraint{fn}},
+		},
+		Subgroups: map[string]*setting.Group{},
+	}
+	c := setting.NewCatalog()
+
+	assert.Equal(t, "pin must satisfy even", c.Render(g.Validate(), "en"))
+
+	g.Elements["pin"].Sensitive = false
+
+	assert.Equal(t, "pin must satisfy even: odd", c.Render(g.Validate(), "en"))
+}
+
+func TestGroupValidateLocale(t *testing.T) {
+	c := setting.NewCatalog()
+	c.Set("fr", setting.Messages{"less": "{path} doit être inférieur à {param}"})
+
+	g := newTestGroup()
+	g.FindElement("A").Value.(*value.Float).Set(12.5)
+
+	assert.NoError(t, newTestGroup().ValidateLocale(c, "fr"))
+
+	err := g.ValidateLocale(c, "fr")
+
+	var localized *setting.LocalizedError
+
+	if assert.True(t, errors.As(err, &localized)) {
+		assert.Equal(t, "fr", localized.Locale)
+		assert.Equal(t, "A doit être inférieur à 10", localized.Message)
+		assert.Equal(t, localized.Message, err.Error())
+	}
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, []string{"A"}, setting.ErrorPath(err))
+
+	g.FindElement("A").Messages = map[string]setting.Messages{
+		"fr": {"less": "A est trop grand"},
+	}
+
+	assert.Equal(t, "A est trop grand", g.ValidateLocale(c, "fr").Error())
+	assert.Equal(t, "A must be less than 10", g.ValidateLocale(c, "en").Error())
+
+	g.FindElement("A").Messages[""] = setting.Messages{"less": "A is too big"}
+
+	assert.Equal(t, "A is too big", g.ValidateLocale(c, "en").Error())
+}
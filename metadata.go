@@ -0,0 +1,61 @@
+package setting
+
+import (
+	"sort"
+	"strings"
+)
+
+// Metadata describes an element for people, and is used when producing
+// usage text, documentation and schemas.
+type Metadata struct {
+	// Description is a human-readable description
+	Description string
+	// Examples are example values, in the form accepted by Value.Parse
+	Examples []string
+	// Unit is a unit label, such as "ms" or "bytes"
+	Unit string
+	// Since is the version where the element was introduced
+	Since string
+	// Tags are arbitrary key/value pairs
+	Tags map[string]string
+}
+
+// TagNames returns the metadata tag names in sorted order.
+func (m *Metadata) TagNames() []string {
+	names := make([]string, 0, len(m.Tags))
+	for name := range m.Tags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// IsEmpty returns true if no metadata is set.
+func (m *Metadata) IsEmpty() bool {
+	return m.Description == "" && len(m.Examples) == 0 && m.Unit == "" &&
+		m.Since == "" && len(m.Tags) == 0
+}
+
+// parseTags parses comma-separated key=value pairs. A key without a value
+// is given an empty value.
+func parseTags(str string) map[string]string {
+	tags := map[string]string{}
+
+	for _, pair := range strings.Split(str, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 1 {
+			tags[kv[0]] = ""
+		} else {
+			tags[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,20 @@
+package setting_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadata(t *testing.T) {
+	m := setting.Metadata{}
+
+	assert.True(t, m.IsEmpty())
+	assert.Empty(t, m.TagNames())
+
+	m.Tags = map[string]string{"team": "core", "area": "net"}
+
+	assert.False(t, m.IsEmpty())
+	assert.Equal(t, []string{"area", "team"}, m.TagNames())
+}
@@ -0,0 +1,387 @@
+package setting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// DefaultVersionKey is the document key of the version number, used by a
+// new Migrator.
+const DefaultVersionKey = "version"
+
+// MigrationStep changes a raw document as part of a migration.
+type MigrationStep interface {
+	// Apply changes the document, returning a description of each change.
+	// Returns a non-nil error in case of failure.
+	Apply(doc map[string]interface{}) ([]string, error)
+}
+
+// MoveStep moves a value, which may be a subtree, to another path.
+type MoveStep struct {
+	from []string
+	to   []string
+}
+
+// NewMoveStep makes a new step that moves a value from one path to another.
+func NewMoveStep(from, to []string) *MoveStep {
+	return &MoveStep{from: from, to: to}
+}
+
+// NewRenameStep makes a new step that renames the last name in a path,
+// such as from "server.listen" to "server.port".
+func NewRenameStep(path []string, name string) *MoveStep {
+	return NewMoveStep(path, appendPath(path[:len(path)-1], name))
+}
+
+// Apply moves the value, if given. Parent maps that become empty are removed.
+// Returns a non-nil error if a value is already given at the new path.
+func (s *MoveStep) Apply(doc map[string]interface{}) ([]string, error) {
+	raw, found := documentGet(doc, s.from)
+	if !found {
+		return nil, nil
+	}
+
+	from, to := strings.Join(s.from, "."), strings.Join(s.to, ".")
+
+	if _, found := documentGet(doc, s.to); found {
+		return nil, fmt.Errorf("cannot move %s to %s: already given", from, to)
+	}
+
+	documentDelete(doc, s.from)
+
+	if !documentSet(doc, s.to, raw) {
+		return nil, fmt.Errorf("cannot move %s to %s: not an object", from, to)
+	}
+
+	return []string{fmt.Sprintf("moved %s to %s", from, to)}, nil
+}
+
+// ConvertStep converts a value (or each value in a list) to another type,
+// such as from the string "8080" to the integer 8080.
+type ConvertStep struct {
+	path []string
+	t    value.Type
+}
+
+// NewConvertStep makes a new step that converts a value to the given type.
+func NewConvertStep(path []string, t value.Type) *ConvertStep {
+	return &ConvertStep{path: path, t: t}
+}
+
+// Apply converts the value, if given.
+// Returns a non-nil error if the value cannot be parsed as the type.
+func (s *ConvertStep) Apply(doc map[string]interface{}) ([]string, error) {
+	raw, found := documentGet(doc, s.path)
+	if !found {
+		return nil, nil
+	}
+
+	path := strings.Join(s.path, ".")
+
+	var converted interface{}
+
+	if items, ok := raw.([]interface{}); ok {
+		convertedItems := make([]interface{}, len(items))
+
+		for i, item := range items {
+			convertedItem, err := convertRaw(item, s.t)
+			if err != nil {
+				return nil, fmt.Errorf("%s: item %d: %w", path, i, err)
+			}
+
+			convertedItems[i] = convertedItem
+		}
+
+		converted = convertedItems
+	} else {
+		var err error
+		if converted, err = convertRaw(raw, s.t); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if reflect.DeepEqual(raw, converted) {
+		return nil, nil
+	}
+
+	documentSet(doc, s.path, converted)
+
+	return []string{fmt.Sprintf("converted %s to %s", path, s.t)}, nil
+}
+
+func convertRaw(raw interface{}, t value.Type) (interface{}, error) {
+	str, err := rawString(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	single := value.NewSingle(t)
+	if err = single.Parse(str); err != nil {
+		return nil, err
+	}
+
+	return single.Value(), nil
+}
+
+// SplitStep splits a string value into a list of strings.
+type SplitStep struct {
+	path []string
+	sep  string
+}
+
+// NewSplitStep makes a new step that splits a string value by the separator,
+// such as "eu, us" into ["eu", "us"] for the separator ",".
+func NewSplitStep(path []string, sep string) *SplitStep {
+	return &SplitStep{path: path, sep: sep}
+}
+
+// Apply splits the value, if it is given as a string. Space around each
+// item is removed, and an empty string becomes an empty list.
+func (s *SplitStep) Apply(doc map[string]interface{}) ([]string, error) {
+	raw, found := documentGet(doc, s.path)
+	if !found {
+		return nil, nil
+	}
+
+	str, ok := raw.(string)
+	if !ok {
+		return nil, nil
+	}
+
+	items := []interface{}{}
+
+	if strings.TrimSpace(str) != "" {
+		for _, item := range strings.Split(str, s.sep) {
+			items = append(items, strings.TrimSpace(item))
+		}
+	}
+
+	documentSet(doc, s.path, items)
+
+	return []string{fmt.Sprintf("split %s into %d items", strings.Join(s.path, "."), len(items))}, nil
+}
+
+// FuncStep changes a document using a function.
+type FuncStep struct {
+	description string
+	f           func(doc map[string]interface{}) (bool, error)
+}
+
+// NewFuncStep makes a new step that calls the function, which returns true
+// if it changed the document. The description is reported for a change.
+func NewFuncStep(description string, f func(doc map[string]interface{}) (bool, error)) *FuncStep {
+	return &FuncStep{description: description, f: f}
+}
+
+// Apply calls the function.
+func (s *FuncStep) Apply(doc map[string]interface{}) ([]string, error) {
+	changed, err := s.f(doc)
+	if err != nil || !changed {
+		return nil, err
+	}
+
+	return []string{s.description}, nil
+}
+
+// MigrationChange describes a change made by a migration step.
+type MigrationChange struct {
+	// Version is the version that the step upgrades to
+	Version     int
+	Description string
+}
+
+// String returns a readable description of the change.
+func (c *MigrationChange) String() string {
+	return fmt.Sprintf("version %d: %s", c.Version, c.Description)
+}
+
+// Migrator upgrades raw documents to the latest version, using the migration
+// steps for each version. A document without a version is version 0.
+type Migrator struct {
+	// VersionKey is the document key of the version number
+	VersionKey string
+
+	steps map[int][]MigrationStep
+}
+
+// NewMigrator makes a new migrator without migration steps, using DefaultVersionKey.
+func NewMigrator() *Migrator {
+	return &Migrator{VersionKey: DefaultVersionKey, steps: map[int][]MigrationStep{}}
+}
+
+// Register adds steps that upgrade a document from the previous version to
+// the given version. Steps are applied in the order they are added.
+func (m *Migrator) Register(version int, steps ...MigrationStep) {
+	m.steps[version] = append(m.steps[version], steps...)
+}
+
+// Latest returns the highest registered version, or 0 if there is none.
+func (m *Migrator) Latest() int {
+	latest := 0
+
+	for version := range m.steps {
+		if version > latest {
+			latest = version
+		}
+	}
+
+	return latest
+}
+
+// Migrate returns a copy of the document that is upgraded to the latest version,
+// and the changes made by each step.
+// Returns a non-nil error if the version is not valid or is newer than the
+// latest version, or a step fails.
+func (m *Migrator) Migrate(doc map[string]interface{}) (map[string]interface{}, []*MigrationChange, error) {
+	version, err := m.version(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	latest := m.Latest()
+	if version > latest {
+		return nil, nil, fmt.Errorf("version %d is newer than the latest version %d", version, latest)
+	}
+
+	doc = copyDocument(doc)
+	changes := []*MigrationChange{}
+
+	for v := version + 1; v <= latest; v++ {
+		for _, step := range m.steps[v] {
+			descriptions, err := step.Apply(doc)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to migrate to version %d: %w", v, err)
+			}
+
+			for _, description := range descriptions {
+				changes = append(changes, &MigrationChange{Version: v, Description: description})
+			}
+		}
+	}
+
+	doc[m.VersionKey] = latest
+
+	return doc, changes, nil
+}
+
+func (m *Migrator) version(doc map[string]interface{}) (int, error) {
+	raw, found := doc[m.VersionKey]
+	if !found {
+		return 0, nil
+	}
+
+	str, err := rawString(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", m.VersionKey, err)
+	}
+
+	version, err := strconv.Atoi(str)
+	if err != nil || version < 0 {
+		return 0, fmt.Errorf("%s: invalid version %q", m.VersionKey, str)
+	}
+
+	return version, nil
+}
+
+// Load migrates the document and loads it into the group, as in Group.Load.
+// The version is not loaded unless the group has an element for it.
+func (m *Migrator) Load(g *Group, doc map[string]interface{}) ([]*MigrationChange, error) {
+	doc, changes, err := m.Migrate(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, found := g.Elements[m.VersionKey]; !found {
+		delete(doc, m.VersionKey)
+	}
+
+	if err = g.Load(doc); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// RewriteFile migrates a JSON or YAML file (by the .json, .yaml or .yml
+// extension) and writes it in place, unless it is already at the latest
+// version. Keys are written in sorted order, and YAML comments are not kept.
+// Returns the changes made, or a non-nil error in case of failure.
+func (m *Migrator) RewriteFile(path string) ([]*MigrationChange, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := readDocumentFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if version, err := m.version(doc); err == nil && version == m.Latest() {
+		return []*MigrationChange{}, nil
+	}
+
+	doc, changes, err := m.Migrate(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+
+	if isJSONFile(path) {
+		data, err = json.MarshalIndent(doc, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(doc)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = writeFileAtomic(path, data, info.Mode()); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// writeFileAtomic writes a file by renaming a temporary file, so that the
+// file is not left partly written.
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	tempPath := f.Name()
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(tempPath, mode)
+	}
+
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+
+	if err != nil {
+		os.Remove(tempPath)
+	}
+
+	return err
+}
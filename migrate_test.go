@@ -0,0 +1,203 @@
+package setting_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMigrateServer struct {
+	Port    int64    `setting:"port"`
+	Regions []string `setting:"regions"`
+}
+
+type testMigrateApp struct {
+	Server testMigrateServer `setting:"server"`
+	Debug  bool              `setting:"debug"`
+}
+
+func newTestMigrator() *setting.Migrator {
+	m := setting.NewMigrator()
+
+	m.Register(1,
+		setting.NewRenameStep([]string{"listen"}, "port"),
+		setting.NewConvertStep([]string{"port"}, value.TypeInt),
+	)
+	m.Register(2,
+		setting.NewMoveStep([]string{"port"}, []string{"server", "port"}),
+		setting.NewSplitStep([]string{"regions"}, ","),
+		setting.NewMoveStep([]string{"regions"}, []string{"server", "regions"}),
+		setting.NewFuncStep("removed verbose", func(doc map[string]interface{}) (bool, error) {
+			verbose, found := doc["verbose"]
+			if !found {
+				return false, nil
+			}
+
+			delete(doc, "verbose")
+			doc["debug"] = verbose
+
+			return true, nil
+		}),
+	)
+
+	return m
+}
+
+func TestMigratorMigrate(t *testing.T) {
+	m := newTestMigrator()
+	doc := map[string]interface{}{"listen": "8080", "regions": "eu, us", "verbose": true}
+
+	assert.Equal(t, 2, m.Latest())
+
+	migrated, changes, err := m.Migrate(doc)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"version": 2,
+		"server":  map[string]interface{}{"port": int64(8080), "regions": []interface{}{"eu", "us"}},
+		"debug":   true,
+	}, migrated)
+	assert.Equal(t, map[string]interface{}{"listen": "8080", "regions": "eu, us", "verbose": true}, doc)
+
+	strs := make([]string, len(changes))
+	for i, change := range changes {
+		strs[i] = change.String()
+	}
+
+	assert.Equal(t, []string{
+		"version 1: moved listen to port",
+		"version 1: converted port to int64",
+		"version 2: moved port to server.port",
+		"version 2: split regions into 2 items",
+		"version 2: moved regions to server.regions",
+		"version 2: removed verbose",
+	}, strs)
+
+	// only later steps apply
+	migrated, changes, err = m.Migrate(map[string]interface{}{"version": 1, "port": 80})
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, map[string]interface{}{
+		"version": 2, "server": map[string]interface{}{"port": 80}}, migrated)
+}
+
+func TestMigratorMigrateErrors(t *testing.T) {
+	m := newTestMigrator()
+
+	testCases := []map[string]interface{}{
+		{"version": 3},
+		{"version": "x"},
+		{"version": -1},
+		{"listen": "abc"},
+		{"version": 1, "port": 1, "server": map[string]interface{}{"port": 2}},
+		{"version": 1, "port": 1, "server": "x"},
+	}
+
+	for _, doc := range testCases {
+		_, _, err := m.Migrate(doc)
+
+		assert.Error(t, err, doc)
+	}
+
+	m.Register(3, setting.NewFuncStep("fail", func(doc map[string]interface{}) (bool, error) {
+		return false, errors.New("failed")
+	}))
+
+	_, _, err := m.Migrate(map[string]interface{}{})
+
+	assert.EqualError(t, err, "failed to migrate to version 3: failed")
+}
+
+func TestMigratorLoad(t *testing.T) {
+	app := &testMigrateApp{}
+	g, err := setting.FromStruct(app)
+
+	assert.NoError(t, err)
+
+	changes, err := newTestMigrator().Load(g, map[string]interface{}{
+		"version": 0, "listen": 8080, "regions": "eu", "verbose": "true"})
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 6)
+	assert.Equal(t, testMigrateApp{
+		Server: testMigrateServer{Port: 8080, Regions: []string{"eu"}},
+		Debug:  true,
+	}, *app)
+}
+
+func TestMigratorRewriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	m := newTestMigrator()
+	jsonPath := filepath.Join(dir, "config.json")
+	yamlPath := filepath.Join(dir, "config.yaml")
+
+	assert.NoError(t, ioutil.WriteFile(jsonPath, []byte(`{"listen": "8080", "debug": true}`), 0640))
+	assert.NoError(t, ioutil.WriteFile(yamlPath, []byte("# old\nversion: 1\nport: 80\n"), 0600))
+
+	changes, err := m.RewriteFile(jsonPath)
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 3)
+
+	data, err := ioutil.ReadFile(jsonPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "debug": true,
+  "server": {
+    "port": 8080
+  },
+  "version": 2
+}
+`, string(data))
+
+	info, err := os.Stat(jsonPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode())
+
+	changes, err = m.RewriteFile(jsonPath)
+
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = m.RewriteFile(yamlPath)
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	data, err = ioutil.ReadFile(yamlPath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "server:\n    port: 80\nversion: 2\n", string(data))
+
+	files, err := ioutil.ReadDir(dir)
+
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+
+	_, err = m.RewriteFile(filepath.Join(dir, "config.toml"))
+
+	assert.Error(t, err)
+
+	txtPath := filepath.Join(dir, "config.txt")
+
+	assert.NoError(t, ioutil.WriteFile(txtPath, []byte("{}"), 0600))
+
+	_, err = m.RewriteFile(txtPath)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package setting
+
+import "reflect"
+
+// IsPresent returns true unless the group is optional and absent.
+func (g *Group) IsPresent() bool {
+	return !g.Optional || g.present
+}
+
+// SetPresent sets whether an optional group is present. For a group made by
+// FromStruct from a struct pointer field, the field is set to nil when absent.
+// Element values are kept while absent, and are used again if made present.
+func (g *Group) SetPresent(present bool) {
+	g.present = present
+
+	if g.setPresent != nil {
+		g.setPresent(present)
+	}
+}
+
+// skipAbsent is a group walk function that skips absent optional groups.
+func skipAbsent(path []string, g *Group) error {
+	if !g.IsPresent() {
+		return SkipGroup
+	}
+
+	return nil
+}
+
+// optionalFromStruct makes an optional group from a struct pointer field,
+// which is present if the pointer is not nil. While absent, element values
+// point to a struct that becomes the field value when made present.
+func optionalFromStruct(rv reflect.Value, path []string) (*Group, error) {
+	// the pointer is kept, so that it can be restored after the field is set to nil
+	ptr := reflect.ValueOf(rv.Interface())
+
+	if rv.IsNil() {
+		ptr = reflect.New(rv.Type().Elem())
+	}
+
+	g, err := groupFromStruct(ptr.Elem(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	g.Optional = true
+	g.present = !rv.IsNil()
+	g.setPresent = func(present bool) {
+		if present {
+			rv.Set(ptr)
+		} else {
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+	}
+
+	return g, nil
+}
@@ -0,0 +1,136 @@
+package setting_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOptionalTLS struct {
+	Cert string `setting:"cert"`
+	Port int64  `setting:"port"`
+}
+
+type testOptionalServer struct {
+	Host string           `setting:"host"`
+	TLS  *testOptionalTLS `setting:"tls"`
+}
+
+func newTestServerGroup(t *testing.T, server *testOptionalServer) *setting.Group {
+	g, err := setting.FromStruct(server)
+
+	assert.NoError(t, err)
+
+	g.Subgroups["tls"].Elements["cert"].Constraints = []constraint.Constraint{
+		constraint.NewMinLen(1),
+	}
+
+	return g
+}
+
+func TestOptionalFromStruct(t *testing.T) {
+	server := &testOptionalServer{Host: "a"}
+	g := newTestServerGroup(t, server)
+	tls := g.Subgroups["tls"]
+
+	assert.True(t, tls.Optional)
+	assert.False(t, tls.IsPresent())
+	assert.True(t, g.IsPresent())
+	assert.NoError(t, g.Validate())
+	assert.Equal(t, "host = a\n", g.String())
+
+	// values are kept while absent
+	assert.NoError(t, g.Set("tls.cert", "c.pem"))
+	assert.Nil(t, server.TLS)
+
+	tls.SetPresent(true)
+
+	assert.Equal(t, &testOptionalTLS{Cert: "c.pem"}, server.TLS)
+	assert.Equal(t, "host = a\ntls.cert = c.pem\ntls.port = 0\n", g.String())
+
+	tls.SetPresent(false)
+
+	assert.Nil(t, server.TLS)
+}
+
+func TestOptionalFromStructPresentAgain(t *testing.T) {
+	tlsStruct := &testOptionalTLS{Cert: "a.pem"}
+	server := &testOptionalServer{Host: "a", TLS: tlsStruct}
+	g := newTestServerGroup(t, server)
+	tls := g.Subgroups["tls"]
+
+	assert.True(t, tls.IsPresent())
+
+	tls.SetPresent(false)
+
+	assert.Nil(t, server.TLS)
+
+	tls.SetPresent(true)
+
+	assert.True(t, tls.IsPresent())
+	assert.Same(t, tlsStruct, server.TLS)
+	assert.NoError(t, g.Set("tls.cert", "b.pem"))
+	assert.Equal(t, "b.pem", server.TLS.Cert)
+}
+
+func TestOptionalValidate(t *testing.T) {
+	server := &testOptionalServer{TLS: &testOptionalTLS{}}
+	g := newTestServerGroup(t, server)
+
+	assert.True(t, g.Subgroups["tls"].IsPresent())
+
+	err := g.Validate()
+
+	assert.True(t, errors.Is(err, constraint.ErrViolation))
+	assert.Equal(t, []string{"tls", "cert"}, setting.ErrorPath(err))
+
+	g.Subgroups["tls"].SetPresent(false)
+
+	assert.NoError(t, g.Validate())
+}
+
+func TestOptionalLoad(t *testing.T) {
+	server := &testOptionalServer{}
+	g := newTestServerGroup(t, server)
+
+	assert.NoError(t, g.LoadJSON(strings.NewReader(`{"tls": {"cert": "c.pem"}}`)))
+	assert.Equal(t, &testOptionalTLS{Cert: "c.pem"}, server.TLS)
+
+	assert.NoError(t, g.LoadJSON(strings.NewReader(`{"tls": null}`)))
+	assert.Nil(t, server.TLS)
+	assert.False(t, g.Subgroups["tls"].IsPresent())
+
+	assert.NoError(t, g.LoadFlat(map[string]string{"tls.port": "443"}))
+	assert.Equal(t, &testOptionalTLS{Cert: "c.pem", Port: 443}, server.TLS)
+}
+
+func TestOptionalManual(t *testing.T) {
+	metrics := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"port": setting.NewElement(value.NewInt(0), constraint.NewGreater(value.NewInt(0))),
+		},
+		Optional: true,
+	}
+	g := &setting.Group{Subgroups: map[string]*setting.Group{"metrics": metrics}}
+
+	assert.NoError(t, g.Validate())
+
+	clone := g.Clone()
+	metrics.SetPresent(true)
+
+	assert.Error(t, g.Validate())
+	assert.NoError(t, clone.Validate())
+
+	count := 0
+	_ = g.Walk(func(path []string, e *setting.Element) error {
+		count++
+		return nil
+	})
+
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,116 @@
+package setting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a name in a path, with an optional slice index.
+type pathSegment struct {
+	name  string
+	index int
+}
+
+const noIndex = -1
+
+// JoinPath makes a dotted path string from path names, escaping any dots,
+// brackets or backslashes in the names.
+func JoinPath(path []string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `.`, `\.`, `[`, `\[`, `]`, `\]`)
+	escaped := make([]string, len(path))
+
+	for i, name := range path {
+		escaped[i] = escaper.Replace(name)
+	}
+
+	return strings.Join(escaped, ".")
+}
+
+// parsePath parses a dotted path string, such as "server.hosts[2]".
+// A backslash escapes the next character, so that names can include dots.
+func parsePath(str string) ([]pathSegment, error) {
+	segments := []pathSegment{}
+	current := pathSegment{index: noIndex}
+
+	var name strings.Builder
+
+	endSegment := func() error {
+		if name.Len() == 0 {
+			return fmt.Errorf("invalid path %q: empty name", str)
+		}
+
+		current.name = name.String()
+		segments = append(segments, current)
+		current = pathSegment{index: noIndex}
+
+		name.Reset()
+
+		return nil
+	}
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; c {
+		case '\\':
+			if i+1 == len(str) {
+				return nil, fmt.Errorf("invalid path %q: trailing backslash", str)
+			}
+
+			i++
+			name.WriteByte(str[i])
+		case '.':
+			if err := endSegment(); err != nil {
+				return nil, err
+			}
+		case '[':
+			end := strings.IndexByte(str[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", str)
+			}
+
+			index, err := strconv.Atoi(str[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %q: bad index %q", str, str[i+1:i+end])
+			}
+
+			current.index = index
+			i += end
+
+			if i+1 < len(str) && str[i+1] != '.' {
+				return nil, fmt.Errorf("invalid path %q: expected . after ]", str)
+			}
+		default:
+			name.WriteByte(c)
+		}
+	}
+
+	if err := endSegment(); err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// lessPath orders dotted path strings by name, and by index for the same
+// name, so that list items are added in order. Invalid paths are ordered as
+// strings.
+func lessPath(a, b string) bool {
+	segmentsA, errA := parsePath(a)
+	segmentsB, errB := parsePath(b)
+
+	if errA != nil || errB != nil {
+		return a < b
+	}
+
+	for i := 0; i < len(segmentsA) && i < len(segmentsB); i++ {
+		if segmentsA[i].name != segmentsB[i].name {
+			return segmentsA[i].name < segmentsB[i].name
+		}
+
+		if segmentsA[i].index != segmentsB[i].index {
+			return segmentsA[i].index < segmentsB[i].index
+		}
+	}
+
+	return len(segmentsA) < len(segmentsB)
+}
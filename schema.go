@@ -0,0 +1,593 @@
+package setting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// SchemaVersion is the version of the schema document format.
+const SchemaVersion = 1
+
+// SchemaFormat is an encoding of schema documents.
+type SchemaFormat int
+
+const (
+	// SchemaJSON encodes schema documents as indented JSON
+	SchemaJSON SchemaFormat = iota
+	// SchemaYAML encodes schema documents as YAML
+	SchemaYAML
+)
+
+// schemaOrderDeclaration is the schema ordering name for OrderDeclaration
+const schemaOrderDeclaration = "declaration"
+
+// schemaDocument is the top-level schema document, which describes the root group.
+type schemaDocument struct {
+	Version     int `json:"version" yaml:"version"`
+	schemaGroup `yaml:",inline"`
+}
+
+type schemaGroup struct {
+	Elements  map[string]*schemaElement `json:"elements,omitempty" yaml:"elements,omitempty"`
+	Subgroups map[string]*schemaGroup   `json:"subgroups,omitempty" yaml:"subgroups,omitempty"`
+	Lists     map[string]*schemaList    `json:"lists,omitempty" yaml:"lists,omitempty"`
+	Maps      map[string]*schemaMap     `json:"maps,omitempty" yaml:"maps,omitempty"`
+	Aliases   []*schemaAlias            `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// Ordering is "declaration" for OrderDeclaration, or empty for OrderLexical
+	Ordering string   `json:"ordering,omitempty" yaml:"ordering,omitempty"`
+	Order    []string `json:"order,omitempty" yaml:"order,omitempty"`
+	Optional bool     `json:"optional,omitempty" yaml:"optional,omitempty"`
+}
+
+type schemaElement struct {
+	Type        string              `json:"type" yaml:"type"`
+	Slice       bool                `json:"slice,omitempty" yaml:"slice,omitempty"`
+	Default     interface{}         `json:"default,omitempty" yaml:"default,omitempty"`
+	Constraints []*schemaConstraint `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	Required    bool                `json:"required,omitempty" yaml:"required,omitempty"`
+	Sensitive   bool                `json:"sensitive,omitempty" yaml:"sensitive,omitempty"`
+	Description string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Examples    []string            `json:"examples,omitempty" yaml:"examples,omitempty"`
+	Unit        string              `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Since       string              `json:"since,omitempty" yaml:"since,omitempty"`
+	Tags        map[string]string   `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Deprecated  *schemaDeprecation  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Messages    map[string]Messages `json:"messages,omitempty" yaml:"messages,omitempty"`
+}
+
+type schemaConstraint struct {
+	Type  string      `json:"type" yaml:"type"`
+	Param interface{} `json:"param,omitempty" yaml:"param,omitempty"`
+	// Tolerance is given for a MultipleOf constraint without the default
+	// tolerance. It is a pointer, so that a zero tolerance is kept.
+	Tolerance *float64 `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+	// Constraints are wrapped by a composite constraint
+	Constraints []*schemaConstraint `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+}
+
+type schemaList struct {
+	Template *schemaGroup `json:"template" yaml:"template"`
+	MinItems int          `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems int          `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+}
+
+type schemaMap struct {
+	Template       *schemaGroup        `json:"template" yaml:"template"`
+	KeyConstraints []*schemaConstraint `json:"keyConstraints,omitempty" yaml:"keyConstraints,omitempty"`
+}
+
+type schemaDeprecation struct {
+	Since       string   `json:"since,omitempty" yaml:"since,omitempty"`
+	RemovedIn   string   `json:"removedIn,omitempty" yaml:"removedIn,omitempty"`
+	Replacement []string `json:"replacement,omitempty" yaml:"replacement,omitempty"`
+	Message     string   `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type schemaAlias struct {
+	Old       []string `json:"old" yaml:"old"`
+	New       []string `json:"new" yaml:"new"`
+	Since     string   `json:"since,omitempty" yaml:"since,omitempty"`
+	RemovedIn string   `json:"removedIn,omitempty" yaml:"removedIn,omitempty"`
+}
+
+// MarshalSchema encodes the group definition as a schema document, which has
+// the value type, constraints (by constraint type name and parameter),
+// default (current) value and metadata of each element, as well as the
+// subgroups, list and map templates, aliases and ordering. List items and map
+// entries are not included, and the defaults of sensitive elements are left out.
+// The output only depends on the group, so it can be diffed between releases.
+// Returns a non-nil error if the format is not known.
+func (g *Group) MarshalSchema(format SchemaFormat) ([]byte, error) {
+	doc := &schemaDocument{Version: SchemaVersion, schemaGroup: *newSchemaGroup(g)}
+
+	switch format {
+	case SchemaJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	case SchemaYAML:
+		var b bytes.Buffer
+
+		e := yaml.NewEncoder(&b)
+		e.SetIndent(2)
+
+		if err := e.Encode(doc); err != nil {
+			return nil, err
+		}
+
+		if err := e.Close(); err != nil {
+			return nil, err
+		}
+
+		return b.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("unknown schema format %d", format)
+}
+
+// UnmarshalSchema makes a group from a schema document made by MarshalSchema.
+// Func constraints are found by name in the given funcs.
+// Returns a non-nil error if the document is not valid, has unknown fields,
+// or has a Func constraint that is not given.
+func UnmarshalSchema(data []byte, format SchemaFormat, funcs ...*constraint.Func) (*Group, error) {
+	doc := &schemaDocument{}
+
+	switch format {
+	case SchemaJSON:
+		d := json.NewDecoder(bytes.NewReader(data))
+		d.UseNumber()
+		d.DisallowUnknownFields()
+
+		if err := d.Decode(doc); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+	case SchemaYAML:
+		d := yaml.NewDecoder(bytes.NewReader(data))
+		d.KnownFields(true)
+
+		if err := d.Decode(doc); err != nil {
+			return nil, fmt.Errorf("failed to decode YAML: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown schema format %d", format)
+	}
+
+	if doc.Version != SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", doc.Version)
+	}
+
+	funcsByName := map[string]*constraint.Func{}
+	for _, f := range funcs {
+		funcsByName[f.Name()] = f
+	}
+
+	return doc.schemaGroup.group([]string{}, funcsByName)
+}
+
+func newSchemaGroup(g *Group) *schemaGroup {
+	sg := &schemaGroup{Order: g.Order, Optional: g.Optional}
+
+	if g.Ordering == OrderDeclaration {
+		sg.Ordering = schemaOrderDeclaration
+	}
+
+	for name, elem := range g.Elements {
+		if sg.Elements == nil {
+			sg.Elements = map[string]*schemaElement{}
+		}
+
+		sg.Elements[name] = newSchemaElement(elem)
+	}
+
+	for name, subgroup := range g.Subgroups {
+		if sg.Subgroups == nil {
+			sg.Subgroups = map[string]*schemaGroup{}
+		}
+
+		sg.Subgroups[name] = newSchemaGroup(subgroup)
+	}
+
+	for name, l := range g.Lists {
+		if sg.Lists == nil {
+			sg.Lists = map[string]*schemaList{}
+		}
+
+		sg.Lists[name] = &schemaList{
+			Template: newSchemaGroup(l.Template),
+			MinItems: l.MinItems,
+			MaxItems: l.MaxItems,
+		}
+	}
+
+	for name, m := range g.Maps {
+		if sg.Maps == nil {
+			sg.Maps = map[string]*schemaMap{}
+		}
+
+		sg.Maps[name] = &schemaMap{
+			Template:       newSchemaGroup(m.Template),
+			KeyConstraints: newSchemaConstraints(m.KeyConstraints),
+		}
+	}
+
+	for _, alias := range g.Aliases {
+		sg.Aliases = append(sg.Aliases, &schemaAlias{
+			Old:       alias.Old,
+			New:       alias.New,
+			Since:     alias.Since,
+			RemovedIn: alias.RemovedIn,
+		})
+	}
+
+	return sg
+}
+
+func newSchemaElement(e *Element) *schemaElement {
+	se := &schemaElement{
+		Type:        e.Value.Type().String(),
+		Slice:       e.Value.IsSlice(),
+		Constraints: newSchemaConstraints(e.Constraints),
+		Required:    e.Required,
+		Sensitive:   e.Sensitive,
+		Description: e.Metadata.Description,
+		Examples:    e.Metadata.Examples,
+		Unit:        e.Metadata.Unit,
+		Since:       e.Metadata.Since,
+		Tags:        e.Metadata.Tags,
+		Messages:    e.Messages,
+	}
+
+	if !e.Sensitive {
+		se.Default = jsonValue(e.Value)
+	}
+
+	if d := e.Deprecated; d != nil {
+		se.Deprecated = &schemaDeprecation{
+			Since:       d.Since,
+			RemovedIn:   d.RemovedIn,
+			Replacement: d.Replacement,
+			Message:     d.Message,
+		}
+	}
+
+	return se
+}
+
+func newSchemaConstraints(constraints []constraint.Constraint) []*schemaConstraint {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	scs := make([]*schemaConstraint, len(constraints))
+
+	for i, c := range constraints {
+		sc := &schemaConstraint{Type: c.Type().String(), Param: jsonValue(c.Param())}
+
+		if composite, ok := c.(constraint.Composite); ok {
+			sc.Constraints = newSchemaConstraints(composite.Constraints())
+		}
+
+		if multipleOf, ok := c.(*constraint.MultipleOf); ok && multipleOf.Tolerance() != constraint.DefaultTolerance {
+			tolerance := multipleOf.Tolerance()
+			sc.Tolerance = &tolerance
+		}
+
+		scs[i] = sc
+	}
+
+	return scs
+}
+
+func (sg *schemaGroup) group(path []string, funcs map[string]*constraint.Func) (*Group, error) {
+	g := &Group{
+		Elements:  map[string]*Element{},
+		Subgroups: map[string]*Group{},
+		Lists:     map[string]*List{},
+		Maps:      map[string]*Map{},
+		Optional:  sg.Optional,
+	}
+
+	switch sg.Ordering {
+	case schemaOrderDeclaration:
+		g.Ordering = OrderDeclaration
+	case "":
+		g.Ordering = OrderLexical
+	default:
+		return nil, fmt.Errorf("%s: unknown ordering %q", strings.Join(path, "."), sg.Ordering)
+	}
+
+	g.Order = sg.Order
+
+	for name, se := range sg.Elements {
+		elem, err := se.element(appendPath(path, name), funcs)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Elements[name] = elem
+	}
+
+	for name, sub := range sg.Subgroups {
+		subgroup, err := sub.group(appendPath(path, name), funcs)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Subgroups[name] = subgroup
+	}
+
+	for name, sl := range sg.Lists {
+		template, err := sl.templateGroup(appendPath(path, name+"[]"), funcs)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Lists[name] = NewList(template, sl.MinItems, sl.MaxItems)
+	}
+
+	for name, sm := range sg.Maps {
+		mapPath := appendPath(path, name)
+
+		if sm.Template == nil {
+			return nil, fmt.Errorf("%s: map has no template", strings.Join(mapPath, "."))
+		}
+
+		template, err := sm.Template.group(appendPath(mapPath, "*"), funcs)
+		if err != nil {
+			return nil, err
+		}
+
+		keyConstraints, err := schemaConstraints(sm.KeyConstraints, value.TypeString, funcs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.Join(mapPath, "."), err)
+		}
+
+		g.Maps[name] = NewMap(template, keyConstraints...)
+	}
+
+	for _, sa := range sg.Aliases {
+		g.Aliases = append(g.Aliases, &Alias{
+			Old:       sa.Old,
+			New:       sa.New,
+			Since:     sa.Since,
+			RemovedIn: sa.RemovedIn,
+		})
+	}
+
+	return g, nil
+}
+
+func (sl *schemaList) templateGroup(path []string, funcs map[string]*constraint.Func) (*Group, error) {
+	if sl.Template == nil {
+		return nil, fmt.Errorf("%s: list has no template", strings.Join(path, "."))
+	}
+
+	return sl.Template.group(path, funcs)
+}
+
+func (se *schemaElement) element(path []string, funcs map[string]*constraint.Func) (*Element, error) {
+	t, found := valueType(se.Type)
+	if !found {
+		return nil, fmt.Errorf("%s: unknown value type %q", strings.Join(path, "."), se.Type)
+	}
+
+	var val value.Value = value.NewSingle(t)
+	if se.Slice {
+		val = value.NewSlice(t)
+	}
+
+	if se.Default != nil {
+		if err := setRaw(val, se.Default); err != nil {
+			return nil, annotateError(err, path, se.Sensitive)
+		}
+	}
+
+	constraints, err := schemaConstraints(se.Constraints, t, funcs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strings.Join(path, "."), err)
+	}
+
+	elem := &Element{
+		Value:       val,
+		Constraints: constraints,
+		Required:    se.Required,
+		Sensitive:   se.Sensitive,
+		Metadata: Metadata{
+			Description: se.Description,
+			Examples:    se.Examples,
+			Unit:        se.Unit,
+			Since:       se.Since,
+			Tags:        se.Tags,
+		},
+		Messages: se.Messages,
+	}
+
+	if d := se.Deprecated; d != nil {
+		elem.Deprecated = &Deprecation{
+			Since:       d.Since,
+			RemovedIn:   d.RemovedIn,
+			Replacement: d.Replacement,
+			Message:     d.Message,
+		}
+	}
+
+	return elem, nil
+}
+
+// schemaConstraints makes constraints for values of the given type (or
+// slices of it).
+func schemaConstraints(
+	scs []*schemaConstraint,
+	t value.Type,
+	funcs map[string]*constraint.Func,
+) ([]constraint.Constraint, error) {
+	if len(scs) == 0 {
+		return nil, nil
+	}
+
+	constraints := make([]constraint.Constraint, len(scs))
+
+	for i, sc := range scs {
+		c, err := sc.constraint(t, funcs)
+		if err != nil {
+			return nil, fmt.Errorf("constraint %s: %w", sc.Type, err)
+		}
+
+		constraints[i] = c
+	}
+
+	return constraints, nil
+}
+
+func (sc *schemaConstraint) constraint(t value.Type, funcs map[string]*constraint.Func) (constraint.Constraint, error) {
+	cType, found := constraintType(sc.Type)
+	if !found {
+		return nil, fmt.Errorf("unknown constraint type")
+	}
+
+	switch cType {
+	case constraint.TypeEach, constraint.TypeAllOf, constraint.TypeAnyOf, constraint.TypeNot:
+		wrapped, err := schemaConstraints(sc.Constraints, t, funcs)
+		if err != nil {
+			return nil, err
+		}
+
+		switch cType {
+		case constraint.TypeEach:
+			return constraint.NewEach(wrapped...), nil
+		case constraint.TypeAllOf:
+			return constraint.NewAllOf(wrapped...), nil
+		case constraint.TypeAnyOf:
+			return constraint.NewAnyOf(wrapped...), nil
+		}
+
+		if len(wrapped) != 1 {
+			return nil, fmt.Errorf("expected 1 wrapped constraint, got %d", len(wrapped))
+		}
+
+		return constraint.NewNot(wrapped[0]), nil
+	case constraint.TypeUnique:
+		return constraint.NewUnique(), nil
+	case constraint.TypeOneOf, constraint.TypeNoneOf:
+		slice := value.NewSlice(t)
+		if err := setRaw(slice, sc.Param); err != nil {
+			return nil, err
+		}
+
+		if cType == constraint.TypeOneOf {
+			return constraint.NewOneOf(slice), nil
+		}
+
+		return constraint.NewNoneOf(slice), nil
+	case constraint.TypeMinLen, constraint.TypeMaxLen:
+		n, err := schemaLen(sc.Param)
+		if err != nil {
+			return nil, err
+		}
+
+		if cType == constraint.TypeMinLen {
+			return constraint.NewMinLen(n), nil
+		}
+
+		return constraint.NewMaxLen(n), nil
+	case constraint.TypeSorted, constraint.TypePattern, constraint.TypeFunc:
+		str, ok := sc.Param.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string parameter, got %T", sc.Param)
+		}
+
+		return stringParamConstraint(cType, str, funcs)
+	}
+
+	single := value.NewSingle(t)
+	if err := setRaw(single, sc.Param); err != nil {
+		return nil, err
+	}
+
+	switch cType {
+	case constraint.TypeGreater:
+		return constraint.NewGreater(single), nil
+	case constraint.TypeGreaterEqual:
+		return constraint.NewGreaterEqual(single), nil
+	case constraint.TypeLess:
+		return constraint.NewLess(single), nil
+	case constraint.TypeLessEqual:
+		return constraint.NewLessEqual(single), nil
+	case constraint.TypeContains:
+		return constraint.NewContains(single), nil
+	case constraint.TypeSumLessEqual:
+		return constraint.NewSumLessEqual(single), nil
+	}
+
+	if sc.Tolerance != nil {
+		return constraint.NewMultipleOfWithTolerance(single, *sc.Tolerance), nil
+	}
+
+	return constraint.NewMultipleOf(single), nil
+}
+
+// stringParamConstraint makes a Sorted, Pattern or Func constraint from its parameter.
+func stringParamConstraint(
+	cType constraint.Type,
+	param string,
+	funcs map[string]*constraint.Func,
+) (constraint.Constraint, error) {
+	switch cType {
+	case constraint.TypeSorted:
+		switch param {
+		case constraint.SortAscending:
+			return constraint.NewSortedAscending(), nil
+		case constraint.SortDescending:
+			return constraint.NewSortedDescending(), nil
+		}
+
+		return nil, fmt.Errorf("unknown sort order %q", param)
+	case constraint.TypePattern:
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return nil, err
+		}
+
+		return constraint.NewPattern(re), nil
+	}
+
+	f, found := funcs[param]
+	if !found {
+		return nil, fmt.Errorf("func %q is not given", param)
+	}
+
+	return f, nil
+}
+
+// valueType finds the value type with the given name, such as "int64".
+func valueType(name string) (value.Type, bool) {
+	for _, t := range value.AllTypes() {
+		if t.String() == name {
+			return t, true
+		}
+	}
+
+	return 0, false
+}
+
+// constraintType finds the constraint type with the given name, such as "greater".
+func constraintType(name string) (constraint.Type, bool) {
+	for _, t := range constraint.AllTypes() {
+		if t.String() == name {
+			return t, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,250 @@
+package setting_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSchemaDocUpstream struct {
+	Host    string  `setting:"host"`
+	Weights []int64 `setting:"weights"`
+}
+
+type testSchemaDocTLS struct {
+	Cert string `setting:"cert"`
+}
+
+type testSchemaDoc struct {
+	Port      uint64                           `setting:"port" desc:"Port to listen on" unit:"port" example:"80;443"`
+	Ratio     float64                          `setting:"ratio"`
+	Debug     bool                             `setting:"debug"`
+	Token     string                           `setting:"token" sensitive:"true"`
+	Regions   []string                         `setting:"regions"`
+	TLS       *testSchemaDocTLS                `setting:"tls"`
+	Upstreams []testSchemaDocUpstream          `setting:"upstreams"`
+	Tenants   map[string]testSchemaDocUpstream `setting:"tenants"`
+}
+
+func newTestSchemaDocGroup(t *testing.T, even *constraint.Func) *setting.Group {
+	g, err := setting.FromStruct(&testSchemaDoc{
+		Port: 8080, Ratio: 0.1, Token: "secret", Regions: []string{"eu", "us"}})
+
+	assert.NoError(t, err)
+
+	port := g.Elements["port"]
+	port.Required = true
+	port.Constraints = []constraint.Constraint{
+		constraint.NewGreater(value.NewUInt(0)),
+		constraint.NewLessEqual(value.NewUInt(65535)),
+	}
+	port.Metadata.Tags = map[string]string{"team": "edge"}
+	port.Messages = map[string]setting.Messages{"": {constraint.GreaterStr: "{path} must be positive"}}
+
+	g.Elements["ratio"].Constraints = []constraint.Constraint{
+		constraint.NewMultipleOfWithTolerance(value.NewFloat(0.05), 1e-6),
+		constraint.NewAnyOf(
+			constraint.NewLess(value.NewFloat(1)),
+			constraint.NewNot(constraint.NewGreaterEqual(value.NewFloat(2))),
+		),
+	}
+	g.Elements["token"].Constraints = []constraint.Constraint{
+		constraint.NewPattern(regexp.MustCompile(`^[a-z]+$`)),
+	}
+	g.Elements["regions"].Constraints = []constraint.Constraint{
+		constraint.NewMinLen(1),
+		constraint.NewUnique(),
+		constraint.NewSortedAscending(),
+		constraint.NewEach(constraint.NewOneOf(value.NewStringSlice("eu", "us"))),
+		constraint.NewContains(value.NewString("eu")),
+	}
+	g.Elements["regions"].Deprecated = &setting.Deprecation{Since: "1.2", Replacement: []string{"zones"}}
+	g.Aliases = []*setting.Alias{{Old: []string{"listen"}, New: []string{"port"}, Since: "1.1"}}
+
+	upstreams := g.Lists["upstreams"]
+	upstreams.MinItems = 1
+	upstreams.MaxItems = 4
+	upstreams.Template.Elements["weights"].Constraints = []constraint.Constraint{
+		constraint.NewSumLessEqual(value.NewInt(100)),
+		constraint.NewNoneOf(value.NewIntSlice(13)),
+		even,
+	}
+
+	g.Maps["tenants"].KeyConstraints = []constraint.Constraint{constraint.NewMaxLen(8)}
+
+	return g
+}
+
+func TestSchemaRoundTrip(t *testing.T) {
+	even := constraint.NewFunc("even", "x is even", func(v value.Value) error { return nil })
+	g := newTestSchemaDocGroup(t, even)
+
+	for _, format := range []setting.SchemaFormat{setting.SchemaJSON, setting.SchemaYAML} {
+		data, err := g.MarshalSchema(format)
+
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		g2, err := setting.UnmarshalSchema(data, format, even)
+
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		data2, err := g2.MarshalSchema(format)
+
+		assert.NoError(t, err)
+		assert.Equal(t, string(data), string(data2))
+
+		for _, name := range []string{"port", "ratio", "regions"} {
+			assert.Equal(t, g.Elements[name], g2.Elements[name], name)
+		}
+
+		assert.Equal(t, g.Names(), g2.Names())
+		assert.Equal(t, g.Aliases, g2.Aliases)
+		assert.Equal(t, g.Subgroups["tls"].Optional, g2.Subgroups["tls"].Optional)
+		assert.Equal(t, g.Lists["upstreams"].Template.Elements["weights"].Constraints,
+			g2.Lists["upstreams"].Template.Elements["weights"].Constraints)
+		assert.Equal(t, 4, g2.Lists["upstreams"].MaxItems)
+		assert.Equal(t, g.Maps["tenants"].KeyConstraints, g2.Maps["tenants"].KeyConstraints)
+
+		// sensitive values are not included
+		assert.Equal(t, value.NewString(""), g2.Elements["token"].Value)
+		assert.True(t, g2.Elements["token"].Sensitive)
+	}
+}
+
+func TestSchemaZeroTolerance(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"step": setting.NewElement(value.NewFloat(0.5),
+				constraint.NewMultipleOfWithTolerance(value.NewFloat(0.5), 0)),
+		},
+	}
+
+	for _, format := range []setting.SchemaFormat{setting.SchemaJSON, setting.SchemaYAML} {
+		data, err := g.MarshalSchema(format)
+
+		assert.NoError(t, err)
+
+		g2, err := setting.UnmarshalSchema(data, format)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, g.Elements["step"].Constraints, g2.Elements["step"].Constraints)
+		}
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"port": setting.NewElement(value.NewInt(8080), constraint.NewGreater(value.NewInt(0))),
+			"tags": setting.NewElement(value.NewStringSlice()),
+		},
+		Subgroups: map[string]*setting.Group{
+			"log": {
+				Elements: map[string]*setting.Element{"level": setting.NewElement(value.NewString("info"))},
+				Optional: true,
+			},
+		},
+	}
+
+	data, err := g.MarshalSchema(setting.SchemaJSON)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "version": 1,
+  "elements": {
+    "port": {
+      "type": "int64",
+      "default": 8080,
+      "constraints": [
+        {
+          "type": "greater",
+          "param": 0
+        }
+      ]
+    },
+    "tags": {
+      "type": "string",
+      "slice": true,
+      "default": []
+    }
+  },
+  "subgroups": {
+    "log": {
+      "elements": {
+        "level": {
+          "type": "string",
+          "default": "info"
+        }
+      },
+      "optional": true
+    }
+  }
+}
+`, string(data))
+
+	data, err = g.MarshalSchema(setting.SchemaYAML)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `version: 1
+elements:
+  port:
+    type: int64
+    default: 8080
+    constraints:
+      - type: greater
+        param: 0
+  tags:
+    type: string
+    slice: true
+    default: []
+subgroups:
+  log:
+    elements:
+      level:
+        type: string
+        default: info
+    optional: true
+`, string(data))
+}
+
+func TestUnmarshalSchemaErrors(t *testing.T) {
+	testCases := map[string]string{
+		`{"version": 2}`:         "unsupported schema version 2",
+		`{"version": 1, "x": 1}`: "failed to decode JSON",
+		`{"version": 1, "elements": {"a": {"type": "int"}}}`:                   `a: unknown value type "int"`,
+		`{"version": 1, "elements": {"a": {"type": "int64", "default": "x"}}}`: "a: ",
+		`{"version": 1, "subgroups": {"b": {"elements": {"a": {"type": "int64",
+			"constraints": [{"type": "bigger", "param": 1}]}}}}}`: "b.a: constraint bigger: unknown constraint type",
+		`{"version": 1, "elements": {"a": {"type": "int64",
+			"constraints": [{"type": "func", "param": "even"}]}}}`: `a: constraint func: func "even" is not given`,
+		`{"version": 1, "elements": {"a": {"type": "int64",
+			"constraints": [{"type": "not"}]}}}`: "a: constraint not: expected 1 wrapped constraint, got 0",
+		`{"version": 1, "lists": {"l": {}}}`:   "l[]: list has no template",
+		`{"version": 1, "ordering": "random"}`: `: unknown ordering "random"`,
+		`{"version": 1, "maps": {"m": {"template": {}, "keyConstraints": [{"type": "sorted", "param": "up"}]}}}`: `m: constraint sorted: unknown sort order "up"`,
+	}
+
+	for doc, expected := range testCases {
+		_, err := setting.UnmarshalSchema([]byte(doc), setting.SchemaJSON)
+
+		if assert.Error(t, err, doc) {
+			assert.Contains(t, err.Error(), expected, doc)
+		}
+	}
+
+	_, err := setting.UnmarshalSchema([]byte("version: 1\nextra: true\n"), setting.SchemaYAML)
+
+	assert.Error(t, err)
+
+	_, err = setting.UnmarshalSchema([]byte("{}"), setting.SchemaFormat(5))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+package setting
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds a group for concurrent use. Readers get a snapshot, which is a
+// group that is never changed, so it can be read by any number of goroutines
+// and gives a consistent view of all settings. Writers change a clone of the
+// current snapshot, which is published as the new snapshot if it is valid.
+//
+// Since snapshots are clones, a group made by FromStruct keeps its struct at
+// the initial values. Use CopyTo to update it, such as from a subscription.
+type Store struct {
+	// mutex serializes writers, and guards pending and delivering
+	mutex    sync.Mutex
+	snapshot atomic.Value
+	group    *Group
+	// pending are the changes of updates not yet given to the subscriptions
+	pending [][]*ValueChange
+	// delivering is true while a goroutine gives pending changes to the
+	// subscriptions
+	delivering bool
+}
+
+// NewStore makes a new store with a clone of the group as the first snapshot.
+// The group is not changed by updates, but subscriptions to it (see
+// Group.OnChanges) are given the changes made by each update. Subscribing is
+// safe while the store is updated.
+func NewStore(g *Group) *Store {
+	s := &Store{group: g}
+
+	s.snapshot.Store(g.Clone())
+
+	return s
+}
+
+// Snapshot returns the current snapshot. The snapshot is shared with every
+// other reader, so changing it (such as by calling Set on it) is a data race.
+// Use Update or Set to make changes.
+func (s *Store) Snapshot() *Group {
+	return s.snapshot.Load().(*Group)
+}
+
+// Update calls the function with a clone of the current snapshot, then
+// validates the clone and publishes it as the new snapshot. Updates are made
+// one at a time, and subscriptions are given the changes of each update in
+// order, after the update is published. A subscription can update the store;
+// the changes of that update are given once the subscriptions have been given
+// the current changes, possibly by another goroutine.
+// Returns a non-nil error if the function or validation fails, in which case
+// the snapshot is not changed.
+func (s *Store) Update(f func(g *Group) error) error {
+	_, _, err := s.update(func(old *Group) (*Group, error) {
+		g := old.Clone()
+
+		if err := f(g); err != nil {
+			return nil, err
+		}
+
+		return g, nil
+	})
+
+	return err
+}
+
+// update publishes the group made by the function from the current snapshot,
+// if it is valid, and gives the changes to the subscriptions.
+// Returns the new snapshot and the changes.
+func (s *Store) update(f func(old *Group) (*Group, error)) (*Group, []*ValueChange, error) {
+	g, changes, err := s.publish(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.deliver()
+
+	return g, changes, nil
+}
+
+// publish stores the group made by the function from the current snapshot, if
+// it is valid, and queues the changes for the subscriptions.
+func (s *Store) publish(f func(old *Group) (*Group, error)) (*Group, []*ValueChange, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	old := s.Snapshot()
+
+	g, err := f(old)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = g.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	s.snapshot.Store(g)
+
+	changes := valueChanges(old.snapshotValues(nil), g.snapshotValues(nil))
+
+	s.pending = append(s.pending, changes)
+
+	return g, changes, nil
+}
+
+// deliver gives the pending changes to the subscriptions, in order, unless
+// another goroutine (or a subscription further up the stack) is already
+// doing so. The mutex is not held while subscriptions are called.
+func (s *Store) deliver() {
+	s.mutex.Lock()
+	if s.delivering {
+		s.mutex.Unlock()
+
+		return
+	}
+
+	s.delivering = true
+
+	for len(s.pending) > 0 {
+		changes := s.pending[0]
+		s.pending = s.pending[1:]
+
+		s.mutex.Unlock()
+		s.group.notifyChanges(changes)
+		s.mutex.Lock()
+	}
+
+	s.delivering = false
+	s.mutex.Unlock()
+}
+
+// Set sets an element value, as in Group.Set, and publishes a new snapshot.
+// Returns a non-nil error if the value cannot be set.
+func (s *Store) Set(path, str string) error {
+	return s.Update(func(g *Group) error {
+		return g.Set(path, str)
+	})
+}
+
+// Load loads a raw document, as in Group.Load, and publishes a new snapshot
+// if it is valid. Returns a non-nil error if the document cannot be loaded or
+// is not valid.
+func (s *Store) Load(doc map[string]interface{}) error {
+	return s.Update(func(g *Group) error {
+		return g.Load(doc)
+	})
+}
+
+// CopyTo copies the element values of the current snapshot to a group with
+// the same elements, such as the initial group of the store. List items, map
+// entries and the presence of optional subgroups are changed to match. The
+// group must not be used by other goroutines while copying.
+// Returns a non-nil error if an element is missing or has a different type,
+// in which case the group can be partly copied.
+func (s *Store) CopyTo(g *Group) error {
+	defer g.syncMaps()
+
+	return g.copyValues(s.Snapshot(), []string{})
+}
+
+// copyValues copies the element values from a group with the same elements.
+func (g *Group) copyValues(from *Group, path []string) error {
+	if g.Optional {
+		g.SetPresent(from.IsPresent())
+	}
+
+	for _, name := range g.ElementNames() {
+		elem, src := g.Elements[name], from.Elements[name]
+		if src == nil {
+			return &NotFoundError{Path: appendPath(path, name), Index: noIndex}
+		}
+
+		if src.Value.IsSlice() != elem.Value.IsSlice() || src.Value.Type() != elem.Value.Type() {
+			return fmt.Errorf("%s: expected %s, got %s",
+				strings.Join(appendPath(path, name), "."), typeName(elem.Value), typeName(src.Value))
+		}
+
+		if err := copyValue(elem.Value, src.Value.Clone()); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range g.SubgroupNames() {
+		src, found := from.Subgroups[name]
+		if !found {
+			return &NotFoundError{Path: appendPath(path, name), Index: noIndex}
+		}
+
+		if err := g.Subgroups[name].copyValues(src, appendPath(path, name)); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range g.ListNames() {
+		list, src := g.Lists[name], from.Lists[name]
+		if src == nil {
+			return &NotFoundError{Path: appendPath(path, name), Index: noIndex}
+		}
+
+		list.Resize(src.Len())
+
+		for i, item := range list.Items {
+			if err := item.copyValues(src.Items[i], appendPath(path, itemName(name, i))); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range g.MapNames() {
+		m, src := g.Maps[name], from.Maps[name]
+		if src == nil {
+			return &NotFoundError{Path: appendPath(path, name), Index: noIndex}
+		}
+
+		for _, key := range m.Keys() {
+			if _, found := src.Entries[key]; !found {
+				m.Remove(key)
+			}
+		}
+
+		for _, key := range src.Keys() {
+			if err := m.Add(key).copyValues(src.Entries[key], appendPath(path, name, key)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package setting_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStoreGroup() *setting.Group {
+	return &setting.Group{
+		Elements: map[string]*setting.Element{
+			"min": setting.NewElement(value.NewInt(0)),
+			"max": setting.NewElement(value.NewInt(0)),
+			"hosts": setting.NewElement(value.NewStringSlice("a"),
+				constraint.NewMaxLen(100)),
+		},
+		Subgroups: map[string]*setting.Group{
+			"server": {
+				Elements: map[string]*setting.Element{
+					"port": setting.NewElement(value.NewInt(8080), constraint.NewGreater(value.NewInt(0))),
+				},
+			},
+		},
+	}
+}
+
+func TestStoreUpdate(t *testing.T) {
+	g := newTestStoreGroup()
+	s := setting.NewStore(g)
+	snapshot := s.Snapshot()
+
+	assert.NotSame(t, g, snapshot)
+	assert.NoError(t, s.Set("server.port", "80"))
+
+	port, _ := s.Snapshot().GetInt("server.port")
+
+	assert.Equal(t, int64(80), port)
+
+	// earlier snapshots and the initial group are not changed
+	port, _ = snapshot.GetInt("server.port")
+
+	assert.Equal(t, int64(8080), port)
+
+	port, _ = g.GetInt("server.port")
+
+	assert.Equal(t, int64(8080), port)
+
+	snapshot = s.Snapshot()
+
+	assert.Error(t, s.Set("server.port", "0"))
+	assert.Error(t, s.Load(map[string]interface{}{"server": map[string]interface{}{"port": -1}}))
+	assert.Error(t, s.Load(map[string]interface{}{"unknown": 1}))
+	assert.Error(t, s.Update(func(g *setting.Group) error {
+		_ = g.Set("min", "5")
+
+		return fmt.Errorf("failed")
+	}))
+
+	assert.Same(t, snapshot, s.Snapshot())
+
+	assert.NoError(t, s.Load(map[string]interface{}{"min": 1, "max": 2}))
+
+	max, _ := s.Snapshot().GetInt("max")
+
+	assert.Equal(t, int64(2), max)
+}
+
+func TestStoreSubscriptions(t *testing.T) {
+	g := newTestStoreGroup()
+	s := setting.NewStore(g)
+	changes := make(chan []*setting.ValueChange, 10)
+
+	_, err := g.NotifyChanges("server.*", changes)
+
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Set("server.port", "1"))
+	assert.NoError(t, s.Set("min", "1"))
+	assert.Error(t, s.Set("server.port", "0"))
+	assert.NoError(t, s.Set("server.port", "2"))
+
+	assert.Len(t, changes, 2)
+	assert.Equal(t, value.NewInt(1), (<-changes)[0].New)
+	assert.Equal(t, value.NewInt(2), (<-changes)[0].New)
+}
+
+func TestStoreSubscriptionUpdates(t *testing.T) {
+	g := newTestStoreGroup()
+	s := setting.NewStore(g)
+	order := []string{}
+
+	_, err := g.OnChanges("*", func(changes []*setting.ValueChange) {
+		for _, change := range changes {
+			order = append(order, fmt.Sprintf("%s=%v",
+				change.Path[0], change.New.(value.Single).Value()))
+		}
+
+		// keep max at least min, updating the store from the subscription
+		snapshot := s.Snapshot()
+		min, _ := snapshot.GetInt("min")
+		max, _ := snapshot.GetInt("max")
+
+		if max < min {
+			assert.NoError(t, s.Set("max", fmt.Sprint(min)))
+		}
+	})
+
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Set("min", "5"))
+	assert.NoError(t, s.Set("min", "3"))
+
+	assert.Equal(t, []string{"min=5", "max=5", "min=3"}, order)
+
+	max, err := s.Snapshot().GetInt("max")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), max)
+}
+
+type testStoreConfig struct {
+	Proxy   testProxy             `setting:"proxy"`
+	Tenants map[string]testTenant `setting:"tenants"`
+	TLS     *testDocsTLS          `setting:"tls"`
+}
+
+func TestStoreCopyTo(t *testing.T) {
+	config := &testStoreConfig{Tenants: map[string]testTenant{"old": {}}}
+	g, err := setting.FromStruct(config)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	g.Subgroups["tls"].Optional = true
+	s := setting.NewStore(g)
+
+	assert.NoError(t, s.Load(map[string]interface{}{
+		"proxy": map[string]interface{}{
+			"name":      "p",
+			"upstreams": []interface{}{map[string]interface{}{"host": "a"}, map[string]interface{}{"port": 2}},
+		},
+		"tenants": map[string]interface{}{"acme": map[string]interface{}{"rate_limit": 5}},
+		"tls":     map[string]interface{}{"port": 443},
+	}))
+
+	// snapshots do not share the struct
+	assert.Equal(t, "", config.Proxy.Name)
+
+	assert.NoError(t, s.CopyTo(g))
+	assert.Equal(t, &testStoreConfig{
+		Proxy:   testProxy{Name: "p", Upstreams: []testUpstream{{Host: "a"}, {Port: 2}}},
+		Tenants: map[string]testTenant{"acme": {RateLimit: 5}},
+		TLS:     &testDocsTLS{Port: 443},
+	}, config)
+
+	assert.NoError(t, s.Load(map[string]interface{}{"tls": nil, "proxy": map[string]interface{}{"upstreams": []interface{}{}}}))
+	assert.NoError(t, s.CopyTo(g))
+	assert.Nil(t, config.TLS)
+	assert.Empty(t, config.Proxy.Upstreams)
+
+	err = s.CopyTo(&setting.Group{Elements: map[string]*setting.Element{"other": setting.NewElement(value.NewInt(0))}})
+
+	assert.True(t, errors.Is(err, setting.ErrNotFound))
+}
+
+// TestStoreSubscribeConcurrent is meant to be run with -race. Goroutines
+// subscribe and unsubscribe while the store is updated.
+func TestStoreSubscribeConcurrent(t *testing.T) {
+	g := newTestStoreGroup()
+	s := setting.NewStore(g)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 1; j <= 50; j++ {
+				assert.NoError(t, s.Set("min", fmt.Sprint(i*50+j)))
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				sub, err := g.OnChanges("min", func(changes []*setting.ValueChange) {})
+
+				assert.NoError(t, err)
+
+				sub.Unsubscribe()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// TestStoreConcurrent is meant to be run with -race. Readers check that
+// each snapshot is consistent while writers update it.
+func TestStoreConcurrent(t *testing.T) {
+	g := newTestStoreGroup()
+	s := setting.NewStore(g)
+	notified := 0
+
+	_, err := g.OnChanges("*", func(changes []*setting.ValueChange) {
+		notified++
+	})
+
+	assert.NoError(t, err)
+
+	const writers, readers, updates = 4, 8, 50
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 1; j <= updates; j++ {
+				n := i*updates + j
+
+				err := s.Update(func(g *setting.Group) error {
+					if err := g.Set("min", fmt.Sprint(n)); err != nil {
+						return err
+					}
+
+					return g.Set("max", fmt.Sprint(n))
+				})
+				assert.NoError(t, err)
+
+				err = s.Load(map[string]interface{}{
+					"hosts":  []interface{}{fmt.Sprint(n), "b"},
+					"server": map[string]interface{}{"port": n},
+				})
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < updates; j++ {
+				snapshot := s.Snapshot()
+
+				min, err1 := snapshot.GetInt("min")
+				max, err2 := snapshot.GetInt("max")
+				hosts, err3 := snapshot.GetStringSlice("hosts")
+
+				assert.NoError(t, err1)
+				assert.NoError(t, err2)
+				assert.NoError(t, err3)
+				assert.Equal(t, min, max)
+				assert.NotEmpty(t, hosts)
+				assert.NoError(t, snapshot.Validate())
+
+				_ = snapshot.String()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 2*writers*updates, notified)
+}
+
+// TestWatcherConcurrent is meant to be run with -race. Readers use the
+// current group while it is reloaded.
+func TestWatcherConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	s := setting.NewStore(newTestStoreGroup())
+	w := setting.NewStoreWatcher(s, path)
+
+	writeTestFile(t, path, `{"min": 0, "max": 0}`)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				_, err := w.Reload()
+				assert.NoError(t, err)
+			}
+		}()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				current := s.Snapshot()
+				min, _ := current.GetInt("min")
+				max, _ := current.GetInt("max")
+
+				assert.Equal(t, min, max)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
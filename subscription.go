@@ -0,0 +1,290 @@
+package setting
+
+import (
+	"sync"
+
+	"github.com/jamestunnell/go-setting/value"
+)
+
+// ValueChange is a change to an element value.
+type ValueChange struct {
+	// Path is the element path, with names such as "upstreams[2]" for list items
+	Path []string
+	// Old is a clone of the old value, or nil for a new element such as in an
+	// added list item
+	Old value.Value
+	// New is a clone of the new value, or nil for a removed element
+	New value.Value
+}
+
+// Subscription receives value changes for the elements that match a path
+// pattern. It is made by Group.OnChange, Group.OnChanges or Group.NotifyChanges.
+type Subscription struct {
+	pattern []string
+	f       func(changes []*ValueChange)
+	subs    *subscriptions
+}
+
+// subscriptions are the subscriptions of a group, in the order they were made.
+type subscriptions struct {
+	mutex sync.Mutex
+	list  []*Subscription
+}
+
+// valueSnapshot is an element path and a clone of its value.
+type valueSnapshot struct {
+	path  []string
+	value value.Value
+}
+
+// OnChange calls the function with the old and new value of each changed
+// element that matches the path pattern (see OnChanges).
+// Returns a non-nil error if the pattern is invalid.
+func (g *Group) OnChange(pattern string, f func(old, new value.Value)) (*Subscription, error) {
+	return g.OnChanges(pattern, func(changes []*ValueChange) {
+		for _, change := range changes {
+			f(change.Old, change.New)
+		}
+	})
+}
+
+// OnChanges calls the function with the changed elements that match the path
+// pattern, each time element values are changed using Set, Load, LoadJSON,
+// LoadFlat or LoadEnv, or by a Store update or Watcher reload. The pattern is
+// a dotted path (see Get), where the name "*" matches any name and a trailing
+// "*" matches a whole subtree, such as "server.*". A list name also matches
+// its items, so "upstreams.*" matches "upstreams[2].port".
+//
+// All of the changes made by one call, such as Load, are given together, in
+// group order followed by any removed elements. Functions are called by the
+// goroutine that made the changes, after they are made, in the order that
+// they subscribed. Functions are not called if no matching element changed.
+// Changes made directly to element values, or through a subgroup, are not
+// seen. Subscriptions are not copied by Clone.
+// Returns a non-nil error if the pattern is invalid.
+func (g *Group) OnChanges(pattern string, f func(changes []*ValueChange)) (*Subscription, error) {
+	segments, err := parsePath(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := g.subs(true)
+	sub := &Subscription{pattern: segmentNames(segments), f: f, subs: subs}
+
+	subs.mutex.Lock()
+	defer subs.mutex.Unlock()
+
+	subs.list = append(subs.list, sub)
+
+	return sub, nil
+}
+
+// NotifyChanges sends the changed elements that match the path pattern on
+// the channel, as in OnChanges. Sending blocks the goroutine that made the
+// changes, so the channel should be buffered or received from promptly.
+// Returns a non-nil error if the pattern is invalid.
+func (g *Group) NotifyChanges(pattern string, ch chan<- []*ValueChange) (*Subscription, error) {
+	return g.OnChanges(pattern, func(changes []*ValueChange) {
+		ch <- changes
+	})
+}
+
+// Unsubscribe stops the subscription. Changes that are being delivered may
+// still be received.
+func (s *Subscription) Unsubscribe() {
+	s.subs.mutex.Lock()
+	defer s.subs.mutex.Unlock()
+
+	for i, sub := range s.subs.list {
+		if sub == s {
+			s.subs.list = append(s.subs.list[:i:i], s.subs.list[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// matches returns true if the element path matches the subscription pattern.
+func (s *Subscription) matches(path []string) bool {
+	for i, name := range s.pattern {
+		if name == "*" && i == len(s.pattern)-1 {
+			return len(path) > i
+		}
+
+		if i >= len(path) || !matchName(name, path[i]) {
+			return false
+		}
+	}
+
+	return len(path) == len(s.pattern)
+}
+
+// matchName returns true if the pattern name is "*", the same name, or the
+// name of the list that has the item.
+func matchName(pattern, name string) bool {
+	if pattern == "*" || pattern == name {
+		return true
+	}
+
+	listName, _, ok := splitItemName(name)
+
+	return ok && listName == pattern
+}
+
+// trackChanges calls the function, then gives any changed element values to
+// the subscriptions, even if the function fails. Only the values of elements
+// that match a subscription are compared.
+func (g *Group) trackChanges(f func() error) error {
+	subs := g.subs(false)
+	if subs == nil {
+		return f()
+	}
+
+	current := subs.current()
+	if len(current) == 0 {
+		return f()
+	}
+
+	match := func(path []string) bool {
+		for _, sub := range current {
+			if sub.matches(path) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	before := g.snapshotValues(match)
+	err := f()
+
+	subs.notify(valueChanges(before, g.snapshotValues(match)))
+
+	return err
+}
+
+// subs returns the subscriptions of the group, which are made if create is
+// true. Returns nil if there are none.
+func (g *Group) subs(create bool) *subscriptions {
+	g.subscriptionsMutex.Lock()
+	defer g.subscriptionsMutex.Unlock()
+
+	if g.subscriptions == nil && create {
+		g.subscriptions = &subscriptions{}
+	}
+
+	return g.subscriptions
+}
+
+// notifyChanges gives the changes to the subscriptions of the group, if any.
+func (g *Group) notifyChanges(changes []*ValueChange) {
+	if subs := g.subs(false); subs != nil {
+		subs.notify(changes)
+	}
+}
+
+// current returns the current subscriptions.
+func (s *subscriptions) current() []*Subscription {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.list
+}
+
+// notify gives each subscription the changes that match its pattern.
+func (s *subscriptions) notify(changes []*ValueChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	for _, sub := range s.current() {
+		matching := []*ValueChange{}
+
+		for _, change := range changes {
+			if sub.matches(change.Path) {
+				matching = append(matching, change)
+			}
+		}
+
+		if len(matching) > 0 {
+			sub.f(matching)
+		}
+	}
+}
+
+// snapshotValues returns clones of the element values in group order, for
+// the elements with paths accepted by the match function (or all elements if
+// it is nil). Elements in absent optional subgroups are left out.
+func (g *Group) snapshotValues(match func(path []string) bool) []*valueSnapshot {
+	snapshots := []*valueSnapshot{}
+
+	_ = g.walk([]string{}, func(path []string, e *Element) error {
+		if match == nil || match(path) {
+			snapshots = append(snapshots, &valueSnapshot{path: path, value: e.Value.Clone()})
+		}
+
+		return nil
+	}, skipAbsent)
+
+	return snapshots
+}
+
+// valueChanges compares element values, giving the changes in the order of
+// the new values followed by removed elements.
+func valueChanges(from, to []*valueSnapshot) []*ValueChange {
+	fromValues := make(map[string]value.Value, len(from))
+	toValues := make(map[string]value.Value, len(to))
+
+	for _, s := range from {
+		fromValues[JoinPath(s.path)] = s.value
+	}
+
+	for _, s := range to {
+		toValues[JoinPath(s.path)] = s.value
+	}
+
+	changes := []*ValueChange{}
+
+	for _, s := range to {
+		old, found := fromValues[JoinPath(s.path)]
+
+		if !found || !valuesEqual(old, s.value) {
+			changes = append(changes, &ValueChange{Path: s.path, Old: old, New: s.value})
+		}
+	}
+
+	for _, s := range from {
+		if _, found := toValues[JoinPath(s.path)]; !found {
+			changes = append(changes, &ValueChange{Path: s.path, Old: s.value})
+		}
+	}
+
+	return changes
+}
+
+// valuesEqual returns true if the values have the same type and are equal.
+func valuesEqual(v1, v2 value.Value) bool {
+	var (
+		equal bool
+		err   error
+	)
+
+	switch v1 := v1.(type) {
+	case value.Single:
+		single, ok := v2.(value.Single)
+		if !ok {
+			return false
+		}
+
+		equal, err = v1.Equal(single)
+	case value.Slice:
+		slice, ok := v2.(value.Slice)
+		if !ok {
+			return false
+		}
+
+		equal, err = v1.Equal(slice)
+	}
+
+	return err == nil && equal
+}
@@ -0,0 +1,268 @@
+package setting_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSubscriptionGroup() *setting.Group {
+	return &setting.Group{
+		Elements: map[string]*setting.Element{
+			"debug": setting.NewElement(value.NewBool(false)),
+		},
+		Subgroups: map[string]*setting.Group{
+			"server": {
+				Elements: map[string]*setting.Element{
+					"host": setting.NewElement(value.NewString("localhost")),
+					"port": setting.NewElement(value.NewInt(8080)),
+				},
+			},
+		},
+		Lists: map[string]*setting.List{
+			"upstreams": setting.NewList(&setting.Group{
+				Elements: map[string]*setting.Element{"port": setting.NewElement(value.NewInt(80))},
+			}, 0, 0),
+		},
+	}
+}
+
+func changePaths(changes []*setting.ValueChange) []string {
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = strings.Join(change.Path, ".")
+	}
+
+	return paths
+}
+
+func TestGroupOnChange(t *testing.T) {
+	g := newTestSubscriptionGroup()
+	olds, news := []value.Value{}, []value.Value{}
+
+	sub, err := g.OnChange("server.port", func(old, new value.Value) {
+		olds = append(olds, old)
+		news = append(news, new)
+	})
+
+	assert.NoError(t, err)
+
+	assert.NoError(t, g.Set("server.port", "9090"))
+	assert.NoError(t, g.Set("server.host", "example.com"))
+	assert.NoError(t, g.Set("server.port", "9090"))
+	assert.Error(t, g.Set("server.port", "x"))
+	assert.NoError(t, g.LoadFlat(map[string]string{"server.port": "1"}))
+
+	assert.Equal(t, []value.Value{value.NewInt(8080), value.NewInt(9090)}, olds)
+	assert.Equal(t, []value.Value{value.NewInt(9090), value.NewInt(1)}, news)
+
+	// values are clones
+	news[0].(*value.Int).Set(5)
+
+	port, _ := g.GetInt("server.port")
+
+	assert.Equal(t, int64(1), port)
+
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+
+	assert.NoError(t, g.Set("server.port", "2"))
+	assert.Len(t, news, 2)
+}
+
+func TestGroupOnChanges(t *testing.T) {
+	g := newTestSubscriptionGroup()
+	events := map[string][][]string{}
+
+	for _, pattern := range []string{"*", "server.*", "upstreams.*", "upstreams[1].port", "*.port"} {
+		pattern := pattern
+
+		_, err := g.OnChanges(pattern, func(changes []*setting.ValueChange) {
+			events[pattern] = append(events[pattern], changePaths(changes))
+		})
+
+		assert.NoError(t, err)
+	}
+
+	err := g.Load(map[string]interface{}{
+		"debug":     true,
+		"server":    map[string]interface{}{"host": "example.com", "port": 80},
+		"upstreams": []interface{}{map[string]interface{}{"port": 81}, map[string]interface{}{}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][][]string{
+		"*":                 {{"debug", "server.host", "server.port", "upstreams[0].port", "upstreams[1].port"}},
+		"server.*":          {{"server.host", "server.port"}},
+		"upstreams.*":       {{"upstreams[0].port", "upstreams[1].port"}},
+		"upstreams[1].port": {{"upstreams[1].port"}},
+		"*.port":            {{"server.port", "upstreams[0].port", "upstreams[1].port"}},
+	}, events)
+
+	events = map[string][][]string{}
+
+	assert.NoError(t, g.Load(map[string]interface{}{"upstreams": []interface{}{}}))
+	assert.Equal(t, map[string][][]string{
+		"*":                 {{"upstreams[0].port", "upstreams[1].port"}},
+		"upstreams.*":       {{"upstreams[0].port", "upstreams[1].port"}},
+		"upstreams[1].port": {{"upstreams[1].port"}},
+		"*.port":            {{"upstreams[0].port", "upstreams[1].port"}},
+	}, events)
+
+	_, err = g.OnChanges("server.", func(changes []*setting.ValueChange) {})
+
+	assert.Error(t, err)
+}
+
+func TestGroupOnChangesRemoved(t *testing.T) {
+	g := newTestSubscriptionGroup()
+	changes := []*setting.ValueChange{}
+
+	_, err := g.OnChanges("upstreams.*", func(c []*setting.ValueChange) {
+		changes = append(changes, c...)
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, g.LoadFlat(map[string]string{"upstreams[0].port": "81"}))
+	assert.NoError(t, g.Load(map[string]interface{}{"upstreams": []interface{}{}}))
+
+	if assert.Len(t, changes, 2) {
+		assert.Nil(t, changes[0].Old)
+		assert.Equal(t, value.NewInt(81), changes[0].New)
+		assert.Equal(t, value.NewInt(81), changes[1].Old)
+		assert.Nil(t, changes[1].New)
+	}
+}
+
+func TestGroupOnChangesSameFormat(t *testing.T) {
+	g := &setting.Group{
+		Elements: map[string]*setting.Element{
+			"tags": setting.NewElement(value.NewStringSlice("a, b")),
+		},
+	}
+	changes := []*setting.ValueChange{}
+
+	_, err := g.OnChanges("tags", func(c []*setting.ValueChange) {
+		changes = append(changes, c...)
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, g.Load(map[string]interface{}{"tags": []interface{}{"a", "b"}}))
+
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, value.NewStringSlice("a, b"), changes[0].Old)
+		assert.Equal(t, value.NewStringSlice("a", "b"), changes[0].New)
+	}
+
+	assert.NoError(t, g.Load(map[string]interface{}{"tags": []interface{}{"a", "b"}}))
+	assert.Len(t, changes, 1)
+}
+
+func TestGroupNotifyChanges(t *testing.T) {
+	g := newTestSubscriptionGroup()
+	ch := make(chan []*setting.ValueChange, 1)
+
+	_, err := g.NotifyChanges("debug", ch)
+
+	assert.NoError(t, err)
+	assert.NoError(t, g.LoadEnv("APP", []string{"APP_DEBUG=true", "APP_SERVER_PORT=1"}))
+
+	changes := <-ch
+
+	assert.Equal(t, []string{"debug"}, changePaths(changes))
+	assert.Equal(t, value.NewBool(false), changes[0].Old)
+	assert.Equal(t, value.NewBool(true), changes[0].New)
+	assert.Empty(t, ch)
+}
+
+func TestGroupOnChangesWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	g := newTestSubscriptionGroup()
+	w := setting.NewWatcher(g, path)
+	events := [][]string{}
+
+	_, err = g.OnChanges("server.*", func(changes []*setting.ValueChange) {
+		events = append(events, changePaths(changes))
+	})
+
+	assert.NoError(t, err)
+
+	writeTestFile(t, path, `{"server": {"port": 1}}`)
+
+	_, err = w.Reload()
+
+	assert.NoError(t, err)
+
+	writeTestFile(t, path, `{"server": {"host": "a"}, "debug": true}`)
+
+	_, err = w.Reload()
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"server.port"}, {"server.host", "server.port"}}, events)
+
+	// the initial group is not changed by reloads
+	port, _ := g.GetInt("server.port")
+
+	assert.Equal(t, int64(8080), port)
+}
+
+// TestGroupOnChangesConcurrent is meant to be run with -race. Goroutines
+// subscribe while a watcher reloads.
+func TestGroupOnChangesConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	g := newTestSubscriptionGroup()
+	w := setting.NewWatcher(g, path)
+
+	writeTestFile(t, path, `{"debug": true}`)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				sub, err := g.OnChanges("*", func(changes []*setting.ValueChange) {})
+
+				assert.NoError(t, err)
+
+				sub.Unsubscribe()
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				_, err := w.Reload()
+
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
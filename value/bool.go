@@ -37,7 +37,7 @@ func (v *Bool) Parse(str string) error {
 	b, err := strconv.ParseBool(str)
 
 	if err != nil {
-		return err
+		return &ParseError{Type: TypeBool, Slice: false, Input: str, Err: err}
 	}
 
 	*v.valPtr = b
@@ -46,7 +46,7 @@ func (v *BoolSlice) Parse(str string) error {
 
 		val, err := strconv.ParseBool(trimmed)
 		if err != nil {
-			return err
+			return &ParseError{Type: TypeBool, Slice: true, Input: str, Err: err}
 		}
 
 		vals[i] = val
@@ -66,6 +66,10 @@ func (v *BoolSlice) Slice() interface{} { return *v.valsPtr }
 // Len returns the number of slice elements.
 func (v *BoolSlice) Len() int { return len(*v.valsPtr) }
 
+// Index returns a copy of the slice element at the given index as a single value.
+// Panics if the index is out of range.
+func (v *BoolSlice) Index(i int) Single { return NewBool((*v.valsPtr)[i]) }
+
 // Equal checks if length and values of given slice equal the current.
 // Returns a non-nil error if types do not match.
 func (v *BoolSlice) Equal(v2 Slice) (bool, error) {
@@ -208,3 +208,12 @@ func testSliceCompareWrongType(t *testing.T, f compareValueFunc, v value.Single)
 
 	assert.Error(t, err)
 }
+
+func TestBoolSliceIndex(t *testing.T) {
+	v := value.NewBoolSlice(true, false)
+	elem := v.Index(1)
+
+	assert.Equal(t, value.TypeBool, elem.Type())
+	assert.Equal(t, bool(false), elem.Value())
+	assert.Panics(t, func() { v.Index(2) })
+}
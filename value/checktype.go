@@ -1,12 +1,9 @@
 package value
 
-import "fmt"
-
-// CheckType returns a non-nil error if the types are not equal.
+// CheckType returns a non-nil *TypeMismatchError if the types are not equal.
 func CheckType(expected, actual Type) error {
 	if expected != actual {
-		return fmt.Errorf(
-			"unexpected type %s, wanted %s", actual.String(), expected.String())
+		return &TypeMismatchError{Expected: expected, Actual: actual}
 	}
 
 	return nil
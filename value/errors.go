@@ -0,0 +1,100 @@
+package value
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Redacted replaces the text of sensitive values.
+const Redacted = "******"
+
+const (
+	// CodeTypeMismatch is the error code for a TypeMismatchError
+	CodeTypeMismatch = "type_mismatch"
+	// CodeParse is the error code for a ParseError
+	CodeParse = "parse_error"
+)
+
+var (
+	// ErrTypeMismatch matches any TypeMismatchError using errors.Is
+	ErrTypeMismatch = errors.New("type mismatch")
+	// ErrParse matches any ParseError using errors.Is
+	ErrParse = errors.New("parse failed")
+)
+
+// TypeMismatchError indicates that a value does not have the expected type.
+type TypeMismatchError struct {
+	// Path locates the setting, if known
+	Path     []string
+	Expected Type
+	Actual   Type
+	// ExpectedSlice and ActualSlice indicate slice values, if known
+	ExpectedSlice bool
+	ActualSlice   bool
+}
+
+// ParseError indicates that a value could not be parsed from a string.
+type ParseError struct {
+	// Path locates the setting, if known
+	Path []string
+	// Type is the value type, or the element type for a slice
+	Type  Type
+	Slice bool
+	Input string
+	Err   error
+	// Redacted hides the input, for a sensitive value
+	Redacted bool
+}
+
+// Error returns the error message.
+func (e *TypeMismatchError) Error() string {
+	msg := fmt.Sprintf("unexpected type %s, wanted %s",
+		typeString(e.Actual, e.ActualSlice), typeString(e.Expected, e.ExpectedSlice))
+
+	return withPath(e.Path, msg)
+}
+
+// Code returns CodeTypeMismatch.
+func (e *TypeMismatchError) Code() string { return CodeTypeMismatch }
+
+// Is returns true for ErrTypeMismatch.
+func (e *TypeMismatchError) Is(target error) bool { return target == ErrTypeMismatch }
+
+// Error returns the error message.
+func (e *ParseError) Error() string {
+	typeStr := typeString(e.Type, e.Slice)
+
+	if e.Redacted {
+		return withPath(e.Path, fmt.Sprintf("cannot parse %s as %s", Redacted, typeStr))
+	}
+
+	msg := fmt.Sprintf("cannot parse %q as %s: %v", e.Input, typeStr, e.Err)
+
+	return withPath(e.Path, msg)
+}
+
+// Code returns CodeParse.
+func (e *ParseError) Code() string { return CodeParse }
+
+// Is returns true for ErrParse.
+func (e *ParseError) Is(target error) bool { return target == ErrParse }
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error { return e.Err }
+
+func typeString(t Type, slice bool) string {
+	if slice {
+		return "[]" + t.String()
+	}
+
+	return t.String()
+}
+
+func withPath(path []string, msg string) string {
+	if len(path) == 0 {
+		return msg
+	}
+
+	return strings.Join(path, ".") + ": " + msg
+}
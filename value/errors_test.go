@@ -0,0 +1,51 @@
+package value_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeMismatchError(t *testing.T) {
+	err := value.CheckType(value.TypeInt, value.TypeString)
+
+	var typeErr *value.TypeMismatchError
+
+	if assert.True(t, errors.As(err, &typeErr)) {
+		assert.Equal(t, value.TypeInt, typeErr.Expected)
+		assert.Equal(t, value.TypeString, typeErr.Actual)
+		assert.Equal(t, value.CodeTypeMismatch, typeErr.Code())
+	}
+
+	assert.True(t, errors.Is(err, value.ErrTypeMismatch))
+	assert.False(t, errors.Is(err, value.ErrParse))
+	assert.Equal(t, "unexpected type string, wanted int64", err.Error())
+
+	typeErr.Path = []string{"server", "port"}
+
+	assert.Equal(t, "server.port: unexpected type string, wanted int64", err.Error())
+}
+
+func TestParseError(t *testing.T) {
+	err := value.NewIntSlice().Parse("1, x")
+
+	var parseErr *value.ParseError
+
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, value.TypeInt, parseErr.Type)
+		assert.True(t, parseErr.Slice)
+		assert.Equal(t, "1, x", parseErr.Input)
+		assert.Equal(t, value.CodeParse, parseErr.Code())
+	}
+
+	assert.True(t, errors.Is(err, value.ErrParse))
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+	assert.Contains(t, err.Error(), "[]int64")
+
+	assert.True(t, errors.Is(value.NewBool(false).Parse("maybe"), value.ErrParse))
+	assert.True(t, errors.Is(value.NewUInt(0).Parse("-1"), value.ErrParse))
+	assert.True(t, errors.Is(value.NewFloat(0).Parse("x"), value.ErrParse))
+}
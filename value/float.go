@@ -37,7 +37,7 @@ func (v *Float) Parse(str string) error {
 	f, err := strconv.ParseFloat(str, 64)
 
 	if err != nil {
-		return err
+		return &ParseError{Type: TypeFloat, Slice: false, Input: str, Err: err}
 	}
 
 	*v.valPtr = f
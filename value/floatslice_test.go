@@ -165,3 +165,12 @@ func testFloatSliceEqual(t *testing.T, vals1, vals2 []float64, expected bool) {
 
 	testSliceEqual(t, s1, s2, expected)
 }
+
+func TestFloatSliceIndex(t *testing.T) {
+	v := value.NewFloatSlice(1.5, -2.5)
+	elem := v.Index(1)
+
+	assert.Equal(t, value.TypeFloat, elem.Type())
+	assert.Equal(t, float64(-2.5), elem.Value())
+	assert.Panics(t, func() { v.Index(2) })
+}
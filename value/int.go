@@ -37,7 +37,7 @@ func (v *Int) Parse(str string) error {
 	i, err := strconv.ParseInt(str, 10, 64)
 
 	if err != nil {
-		return err
+		return &ParseError{Type: TypeInt, Slice: false, Input: str, Err: err}
 	}
 
 	*v.valPtr = i
@@ -48,7 +48,7 @@ func (v *IntSlice) Parse(str string) error {
 
 		val, err := strconv.ParseInt(substr, 10, 64)
 		if err != nil {
-			return err
+			return &ParseError{Type: TypeInt, Slice: true, Input: str, Err: err}
 		}
 
 		vals[i] = val
@@ -68,6 +68,10 @@ func (v *IntSlice) Slice() interface{} { return *v.valsPtr }
 // Len returns the number of slice elements.
 func (v *IntSlice) Len() int { return len(*v.valsPtr) }
 
+// Index returns a copy of the slice element at the given index as a single value.
+// Panics if the index is out of range.
+func (v *IntSlice) Index(i int) Single { return NewInt((*v.valsPtr)[i]) }
+
 // Equal checks if length and values of given slice equal the current.
 // Returns a non-nil error if types do not match.
 func (v *IntSlice) Equal(v2 Slice) (bool, error) {
@@ -168,3 +168,12 @@ func testIntSliceEqual(t *testing.T, vals1, vals2 []int64, expected bool) {
 
 	testSliceEqual(t, s1, s2, expected)
 }
+
+func TestIntSliceIndex(t *testing.T) {
+	v := value.NewIntSlice(7, -13)
+	elem := v.Index(1)
+
+	assert.Equal(t, value.TypeInt, elem.Type())
+	assert.Equal(t, int64(-13), elem.Value())
+	assert.Panics(t, func() { v.Index(2) })
+}
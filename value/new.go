@@ -0,0 +1,39 @@
+package value
+
+// NewSingle makes a new single value of the given type, set to the zero value.
+// Returns nil if the type is not valid.
+func NewSingle(t Type) Single {
+	switch t {
+	case TypeInt:
+		return NewInt(0)
+	case TypeUInt:
+		return NewUInt(0)
+	case TypeFloat:
+		return NewFloat(0.0)
+	case TypeBool:
+		return NewBool(false)
+	case TypeString:
+		return NewString("")
+	}
+
+	return nil
+}
+
+// NewSlice makes a new empty slice value of the given type.
+// Returns nil if the type is not valid.
+func NewSlice(t Type) Slice {
+	switch t {
+	case TypeInt:
+		return NewIntSlice()
+	case TypeUInt:
+		return NewUIntSlice()
+	case TypeFloat:
+		return NewFloatSlice()
+	case TypeBool:
+		return NewBoolSlice()
+	case TypeString:
+		return NewStringSlice()
+	}
+
+	return nil
+}
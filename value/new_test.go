@@ -0,0 +1,35 @@
+package value_test
+
+import (
+	"testing"
+
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSingle(t *testing.T) {
+	for _, typ := range value.AllTypes() {
+		v := value.NewSingle(typ)
+
+		if assert.NotNil(t, v) {
+			assert.Equal(t, typ, v.Type())
+			assert.False(t, v.IsSlice())
+		}
+	}
+
+	assert.Nil(t, value.NewSingle(value.Type(-1)))
+}
+
+func TestNewSlice(t *testing.T) {
+	for _, typ := range value.AllTypes() {
+		v := value.NewSlice(typ)
+
+		if assert.NotNil(t, v) {
+			assert.Equal(t, typ, v.Type())
+			assert.True(t, v.IsSlice())
+			assert.Equal(t, 0, v.Len())
+		}
+	}
+
+	assert.Nil(t, value.NewSlice(value.Type(-1)))
+}
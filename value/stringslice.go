@@ -58,6 +58,10 @@ func (v *StringSlice) Slice() interface{} { return *v.valsPtr }
 // Len returns the number of slice elements.
 func (v *StringSlice) Len() int { return len(*v.valsPtr) }
 
+// Index returns a copy of the slice element at the given index as a single value.
+// Panics if the index is out of range.
+func (v *StringSlice) Index(i int) Single { return NewString((*v.valsPtr)[i]) }
+
 // Equal checks if length and values of given slice equal the current.
 // Returns a non-nil error if types do not match.
 func (v *StringSlice) Equal(v2 Slice) (bool, error) {
@@ -172,3 +172,12 @@ func testStringSliceEqual(t *testing.T, vals1, vals2 []string, expected bool) {
 
 	testSliceEqual(t, s1, s2, expected)
 }
+
+func TestStringSliceIndex(t *testing.T) {
+	v := value.NewStringSlice("a", "b")
+	elem := v.Index(1)
+
+	assert.Equal(t, value.TypeString, elem.Type())
+	assert.Equal(t, string("b"), elem.Value())
+	assert.Panics(t, func() { v.Index(2) })
+}
@@ -37,7 +37,7 @@ func (v *UInt) Parse(str string) error {
 	u, err := strconv.ParseUint(str, 10, 64)
 
 	if err != nil {
-		return err
+		return &ParseError{Type: TypeUInt, Slice: false, Input: str, Err: err}
 	}
 
 	*v.valPtr = u
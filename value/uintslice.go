@@ -48,7 +48,7 @@ func (v *UIntSlice) Parse(str string) error {
 
 		val, err := strconv.ParseUint(substr, 10, 64)
 		if err != nil {
-			return err
+			return &ParseError{Type: TypeUInt, Slice: true, Input: str, Err: err}
 		}
 
 		vals[i] = val
@@ -68,6 +68,10 @@ func (v *UIntSlice) Slice() interface{} { return *v.valsPtr }
 // Len returns the number of slice elements.
 func (v *UIntSlice) Len() int { return len(*v.valsPtr) }
 
+// Index returns a copy of the slice element at the given index as a single value.
+// Panics if the index is out of range.
+func (v *UIntSlice) Index(i int) Single { return NewUInt((*v.valsPtr)[i]) }
+
 // Equal checks if length and values of given slice equal the current.
 // Returns a non-nil error if types do not match.
 func (v *UIntSlice) Equal(v2 Slice) (bool, error) {
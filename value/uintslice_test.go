@@ -166,3 +166,12 @@ func testUIntSliceEqual(t *testing.T, vals1, vals2 []uint64, expected bool) {
 
 	testSliceEqual(t, s1, s2, expected)
 }
+
+func TestUIntSliceIndex(t *testing.T) {
+	v := value.NewUIntSlice(7, 13)
+	elem := v.Index(1)
+
+	assert.Equal(t, value.TypeUInt, elem.Type())
+	assert.Equal(t, uint64(13), elem.Value())
+	assert.Panics(t, func() { v.Index(2) })
+}
@@ -45,6 +45,7 @@ type Slice interface {
 	SlicePointer() interface{}
 	Slice() interface{}
 	Len() int
+	Index(int) Single
 	Equal(Slice) (bool, error)
 	Contains(Single) (bool, error)
 }
@@ -0,0 +1,162 @@
+package setting
+
+import "errors"
+
+// Ordering determines the order that group elements and subgroups are visited.
+type Ordering int
+
+const (
+	// OrderLexical visits elements in name order, then subgroups in name order,
+	// then lists in name order, then maps in name order. Map entries are visited
+	// in key order.
+	OrderLexical Ordering = iota
+	// OrderDeclaration visits elements and subgroups in the order of their names
+	// in Group.Order, followed by any others in lexical order.
+	OrderDeclaration
+)
+
+// SkipGroup can be returned by a walk function to skip the rest of the
+// current group. When returned for a subgroup, that subgroup is skipped.
+var SkipGroup = errors.New("skip this group")
+
+// WalkFunc is called for each element visited by Group.Walk.
+type WalkFunc func(path []string, e *Element) error
+
+// GroupWalkFunc is called for each group visited by Group.WalkGroups.
+// List items and map entries are visited as groups, with paths such as
+// "upstreams[2]" or "tenants", "acme".
+type GroupWalkFunc func(path []string, g *Group) error
+
+// Names returns the names of the elements, subgroups, lists and maps, in the
+// group order.
+func (g *Group) Names() []string {
+	names := make([]string, 0, len(g.Elements)+len(g.Subgroups)+len(g.Lists)+len(g.Maps))
+	seen := map[string]bool{}
+
+	if g.Ordering == OrderDeclaration {
+		for _, name := range g.Order {
+			if seen[name] {
+				continue
+			}
+
+			_, isElem := g.Elements[name]
+			_, isGroup := g.Subgroups[name]
+			_, isList := g.Lists[name]
+			_, isMap := g.Maps[name]
+
+			if isElem || isGroup || isList || isMap {
+				names = append(names, name)
+				seen[name] = true
+			}
+		}
+	}
+
+	for _, others := range [][]string{g.ElementNames(), g.SubgroupNames(), g.ListNames(), g.MapNames()} {
+		for _, name := range others {
+			if !seen[name] {
+				names = append(names, name)
+				seen[name] = true
+			}
+		}
+	}
+
+	return names
+}
+
+// Walk calls the function for each element (including those in subgroups,
+// list items and map entries), in the group order. If the function returns
+// SkipGroup, the rest of the group containing the element is skipped. Any
+// other error stops the walk. Absent optional subgroups are also visited.
+// Returns the error that stopped the walk, if any.
+func (g *Group) Walk(f WalkFunc) error {
+	return g.walk([]string{}, f, nil)
+}
+
+// WalkGroups calls the function for the group and each subgroup (recursively),
+// in the group order. The group itself has an empty path. If the function returns
+// SkipGroup, that group's subgroups are skipped. Any other error stops the walk.
+// Absent optional subgroups are also visited, so the function can check
+// Group.IsPresent to skip them.
+// Returns the error that stopped the walk, if any.
+func (g *Group) WalkGroups(f GroupWalkFunc) error {
+	err := f([]string{}, g)
+	if err == SkipGroup {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return g.walk([]string{}, nil, f)
+}
+
+func (g *Group) walk(path []string, f WalkFunc, gf GroupWalkFunc) error {
+	for _, name := range g.Names() {
+		namePath := appendPath(path, name)
+
+		if elem, found := g.Elements[name]; found {
+			if f == nil {
+				continue
+			}
+
+			err := f(namePath, elem)
+			if err == SkipGroup {
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if subgroup, found := g.Subgroups[name]; found {
+			if err := subgroup.walkGroup(namePath, f, gf); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if list, found := g.Lists[name]; found {
+			for i, item := range list.Items {
+				itemPath := appendPath(path, itemName(name, i))
+
+				if err := item.walkGroup(itemPath, f, gf); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		m := g.Maps[name]
+
+		for _, key := range m.Keys() {
+			if err := m.Entries[key].walkGroup(appendPath(namePath, key), f, gf); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkGroup calls the group function (if any) for a subgroup or list item,
+// and then walks it unless skipped.
+func (g *Group) walkGroup(path []string, f WalkFunc, gf GroupWalkFunc) error {
+	if gf != nil {
+		err := gf(path, g)
+		if err == SkipGroup {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return g.walk(path, f, gf)
+}
@@ -0,0 +1,149 @@
+package setting_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/stretchr/testify/assert"
+)
+
+type testWalkInner struct {
+	Z int64 `setting:"z"`
+	Y int64 `setting:"y"`
+}
+
+type testWalk struct {
+	Name  string        `setting:"name"`
+	Inner testWalkInner `setting:"inner"`
+	Age   int64         `setting:"age"`
+}
+
+func newTestWalkGroup() *setting.Group {
+	return &setting.Group{
+		Elements: newTestGroup().Elements,
+		Subgroups: map[string]*setting.Group{
+			"Y": newTestGroup(),
+			"X": newTestGroup(),
+		},
+	}
+}
+
+func walkPaths(t *testing.T, g *setting.Group) []string {
+	paths := []string{}
+
+	err := g.Walk(func(path []string, e *setting.Element) error {
+		paths = append(paths, strings.Join(path, "."))
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+
+	return paths
+}
+
+func TestWalkLexical(t *testing.T) {
+	g := newTestWalkGroup()
+
+	assert.Equal(t, []string{"A", "B", "X", "Y"}, g.Names())
+	assert.Equal(t, []string{"A", "B", "X.A", "X.B", "Y.A", "Y.B"}, walkPaths(t, g))
+}
+
+func TestWalkDeclaration(t *testing.T) {
+	g := newTestWalkGroup()
+	g.Ordering = setting.OrderDeclaration
+	g.Order = []string{"Y", "B", "missing"}
+
+	assert.Equal(t, []string{"Y", "B", "A", "X"}, g.Names())
+	assert.Equal(t, []string{"Y.A", "Y.B", "B", "A", "X.A", "X.B"}, walkPaths(t, g))
+}
+
+func TestNamesShared(t *testing.T) {
+	g := newTestWalkGroup()
+	g.Subgroups["A"] = &setting.Group{}
+
+	assert.Equal(t, []string{"A", "B", "X", "Y"}, g.Names())
+}
+
+func TestWalkFromStruct(t *testing.T) {
+	g, err := setting.FromStruct(&testWalk{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "inner.z", "inner.y", "age"}, walkPaths(t, g))
+	assert.Equal(t, "name = \ninner.z = 0\ninner.y = 0\nage = 0\n", g.String())
+}
+
+func TestWalkSkipGroup(t *testing.T) {
+	g := newTestWalkGroup()
+	paths := []string{}
+
+	err := g.Walk(func(path []string, e *setting.Element) error {
+		paths = append(paths, strings.Join(path, "."))
+
+		if len(path) == 2 && path[1] == "A" {
+			return setting.SkipGroup
+		}
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A", "B", "X.A", "Y.A"}, paths)
+}
+
+func TestWalkStopsOnError(t *testing.T) {
+	g := newTestWalkGroup()
+	errStop := errors.New("stop")
+	paths := []string{}
+
+	err := g.Walk(func(path []string, e *setting.Element) error {
+		paths = append(paths, strings.Join(path, "."))
+
+		if len(paths) == 3 {
+			return errStop
+		}
+
+		return nil
+	})
+
+	assert.True(t, errors.Is(err, errStop))
+	assert.Equal(t, []string{"A", "B", "X.A"}, paths)
+}
+
+func TestWalkGroups(t *testing.T) {
+	g := &setting.Group{
+		Subgroups: map[string]*setting.Group{
+			"Y": newTestWalkGroup(),
+			"X": newTestWalkGroup(),
+		},
+	}
+	paths := []string{}
+
+	err := g.WalkGroups(func(path []string, sub *setting.Group) error {
+		paths = append(paths, strings.Join(path, "."))
+
+		if len(path) == 1 && path[0] == "X" {
+			return setting.SkipGroup
+		}
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "X", "Y", "Y.X", "Y.Y"}, paths)
+}
+
+func TestWalkGroupsSkipRoot(t *testing.T) {
+	count := 0
+
+	err := newTestWalkGroup().WalkGroups(func(path []string, sub *setting.Group) error {
+		count++
+
+		return setting.SkipGroup
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,304 @@
+package setting
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the interval between file checks, used by a new Watcher.
+const DefaultPollInterval = time.Second
+
+// ErrWatcherStarted is returned by Watcher.Start if the watcher is already started.
+var ErrWatcherStarted = errors.New("watcher already started")
+
+// ReloadFunc is called by a Watcher after a reload, with the new group and
+// the dotted paths of the elements whose values changed.
+type ReloadFunc func(g *Group, changed []string)
+
+// Watcher reloads a group from a JSON or YAML file when the file changes.
+// Each reload loads the file into a clone of the initial group, and publishes
+// the clone to a store (see Store) only if it is valid. Invalid reloads are
+// reported to the error functions, and the current snapshot is kept.
+//
+// The initial group is never changed by reloads, so the store is the only
+// source of reloaded values. Since clones do not share backing pointers, this
+// also means that a group made by FromStruct keeps its struct at the initial
+// values, unless they are copied using Store.CopyTo. Subscriptions to the
+// initial group (see Group.OnChanges) are given the changes from one snapshot
+// to the next, not changes to the initial group.
+type Watcher struct {
+	// PollInterval is the interval between file checks
+	PollInterval time.Duration
+	// Notify also checks the file when notified of changes by the file
+	// system (using inotify on Linux), instead of only polling. It is
+	// ignored where not supported.
+	Notify bool
+	// Migrator, if not nil, migrates each document before it is loaded
+	Migrator *Migrator
+
+	path  string
+	base  *Group
+	store *Store
+	// reloadMutex serializes reloads, so that subscribers see them in order
+	reloadMutex sync.Mutex
+
+	mutex       sync.RWMutex
+	data        []byte
+	readErr     string
+	subscribers []ReloadFunc
+	errorFuncs  []func(error)
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// NewWatcher makes a new watcher for the file, using DefaultPollInterval,
+// which publishes to a new store for the group (see NewStore).
+func NewWatcher(g *Group, path string) *Watcher {
+	return NewStoreWatcher(NewStore(g), path)
+}
+
+// NewStoreWatcher makes a new watcher for the file, using DefaultPollInterval,
+// which publishes to the store. A clone of the initial group of the store is
+// the starting point for each reload, so a reload replaces any values set
+// in the store since the last reload.
+func NewStoreWatcher(s *Store, path string) *Watcher {
+	return &Watcher{
+		PollInterval: DefaultPollInterval,
+		path:         path,
+		base:         s.group.Clone(),
+		store:        s,
+	}
+}
+
+// Store returns the store that reloads are published to.
+func (w *Watcher) Store() *Store {
+	return w.store
+}
+
+// Current returns the current snapshot of the store. It must not be changed.
+func (w *Watcher) Current() *Group {
+	return w.store.Snapshot()
+}
+
+// Subscribe adds a function to call after each reload that changes an element
+// value. Functions are called in the order they are added, by the goroutine
+// that reloads, and one reload at a time. They must not call Reload.
+func (w *Watcher) Subscribe(f ReloadFunc) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.subscribers = append(w.subscribers, f)
+}
+
+// OnError adds a function to call when a reload fails. Functions are called
+// in the order they are added, by the goroutine that reloads.
+func (w *Watcher) OnError(f func(error)) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.errorFuncs = append(w.errorFuncs, f)
+}
+
+// Reload reads the file and publishes it to the store if it is valid, even if
+// the file has not changed.
+// Returns the dotted paths of the elements whose values changed, or a non-nil
+// error if the file cannot be read, loaded or validated.
+func (w *Watcher) Reload() ([]string, error) {
+	w.reloadMutex.Lock()
+	defer w.reloadMutex.Unlock()
+
+	data, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		return nil, w.fail(err)
+	}
+
+	w.mutex.Lock()
+	w.data, w.readErr = data, ""
+	w.mutex.Unlock()
+
+	return w.reload(data)
+}
+
+// Start reloads the file, then checks it for changes in a new goroutine
+// until Stop is called. The file is reloaded only when its contents change.
+// Returns a non-nil error if the first reload fails or the watcher is
+// already started.
+func (w *Watcher) Start() error {
+	stop, done := make(chan struct{}), make(chan struct{})
+
+	w.mutex.Lock()
+
+	if w.stop != nil {
+		w.mutex.Unlock()
+
+		return ErrWatcherStarted
+	}
+
+	w.stop, w.done = stop, done
+	w.mutex.Unlock()
+
+	var events <-chan struct{}
+
+	if w.Notify {
+		// polling continues if notifications are not available
+		events, _ = notifyChanges(w.path, stop)
+	}
+
+	if _, err := w.Reload(); err != nil {
+		w.mutex.Lock()
+		owned := w.stop == stop
+
+		if owned {
+			w.stop, w.done = nil, nil
+		}
+
+		w.mutex.Unlock()
+
+		// otherwise Stop was called, and has closed stop
+		if owned {
+			close(stop)
+		}
+
+		close(done)
+
+		return err
+	}
+
+	go w.run(events, stop, done)
+
+	return nil
+}
+
+// Stop stops checking the file, and waits for a reload in progress to finish.
+func (w *Watcher) Stop() {
+	w.mutex.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+}
+
+func (w *Watcher) run(events <-chan struct{}, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		case <-events:
+		}
+
+		w.check()
+	}
+}
+
+// check reloads the file if its contents changed since the last check.
+// A read error is only reported once, until the file can be read again.
+func (w *Watcher) check() {
+	w.reloadMutex.Lock()
+	defer w.reloadMutex.Unlock()
+
+	data, err := ioutil.ReadFile(w.path)
+
+	w.mutex.Lock()
+
+	if err != nil {
+		repeated := w.readErr == err.Error()
+
+		w.data, w.readErr = nil, err.Error()
+		w.mutex.Unlock()
+
+		if !repeated {
+			_ = w.fail(err)
+		}
+
+		return
+	}
+
+	changed := w.readErr != "" || !bytes.Equal(data, w.data)
+
+	w.data, w.readErr = data, ""
+	w.mutex.Unlock()
+
+	if changed {
+		_, _ = w.reload(data)
+	}
+}
+
+func (w *Watcher) reload(data []byte) ([]string, error) {
+	doc, err := decodeDocument(w.path, data)
+	if err != nil {
+		return nil, w.fail(err)
+	}
+
+	if w.Migrator != nil {
+		if doc, _, err = w.Migrator.Migrate(doc); err != nil {
+			return nil, w.fail(err)
+		}
+
+		if _, found := w.base.Elements[w.Migrator.VersionKey]; !found {
+			delete(doc, w.Migrator.VersionKey)
+		}
+	}
+
+	g, changes, err := w.store.update(func(*Group) (*Group, error) {
+		g := w.base.Clone()
+
+		if err := g.Load(doc); err != nil {
+			return nil, err
+		}
+
+		return g, nil
+	})
+	if err != nil {
+		return nil, w.fail(err)
+	}
+
+	w.mutex.RLock()
+	subscribers := w.subscribers
+	w.mutex.RUnlock()
+
+	changed := make([]string, len(changes))
+
+	for i, change := range changes {
+		changed[i] = strings.Join(change.Path, ".")
+	}
+
+	if len(changed) > 0 {
+		for _, f := range subscribers {
+			f(g, changed)
+		}
+	}
+
+	return changed, nil
+}
+
+// fail reports a reload error to the error functions, and returns it.
+func (w *Watcher) fail(err error) error {
+	err = fmt.Errorf("failed to reload %s: %w", w.path, err)
+
+	w.mutex.RLock()
+	errorFuncs := w.errorFuncs
+	w.mutex.RUnlock()
+
+	for _, f := range errorFuncs {
+		f(err)
+	}
+
+	return err
+}
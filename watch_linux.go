@@ -0,0 +1,73 @@
+package setting
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// notifyChanges sends on the returned channel when inotify reports a change
+// to the file, until stop is closed. The directory is watched, so that the
+// file can be replaced by renaming another file.
+// Returns a non-nil error if inotify cannot be used.
+func notifyChanges(path string, stop <-chan struct{}) (<-chan struct{}, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	const mask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+		syscall.IN_MODIFY | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+	if _, err = syscall.InotifyAddWatch(fd, filepath.Dir(path), mask); err != nil {
+		syscall.Close(fd)
+
+		return nil, os.NewSyscallError("inotify_add_watch", err)
+	}
+
+	// a non-blocking file uses the runtime poller, so closing it ends a read
+	f := os.NewFile(uintptr(fd), "inotify")
+	name := []byte(filepath.Base(path))
+	events := make(chan struct{}, 1)
+
+	go func() {
+		<-stop
+		f.Close()
+	}()
+
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				start := offset + syscall.SizeofInotifyEvent
+				end := start + int(event.Len)
+
+				if end > n {
+					break
+				}
+
+				offset = end
+
+				if !bytes.Equal(bytes.TrimRight(buf[start:end], "\x00"), name) {
+					continue
+				}
+
+				select {
+				case events <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
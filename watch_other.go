@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package setting
+
+import "errors"
+
+// notifyChanges is not supported on this platform, so only polling is used.
+func notifyChanges(path string, stop <-chan struct{}) (<-chan struct{}, error) {
+	return nil, errors.New("file notifications are not supported")
+}
@@ -0,0 +1,273 @@
+package setting_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jamestunnell/go-setting"
+	"github.com/jamestunnell/go-setting/constraint"
+	"github.com/jamestunnell/go-setting/value"
+	"github.com/stretchr/testify/assert"
+)
+
+type testReload struct {
+	group   *setting.Group
+	changed []string
+}
+
+func newTestWatchGroup() *setting.Group {
+	return &setting.Group{
+		Elements: map[string]*setting.Element{
+			"port":  setting.NewElement(value.NewInt(8080), constraint.NewGreater(value.NewInt(0))),
+			"debug": setting.NewElement(value.NewBool(false)),
+		},
+		Subgroups: map[string]*setting.Group{
+			"tls": {
+				Elements: map[string]*setting.Element{"cert": setting.NewElement(value.NewString(""))},
+			},
+		},
+	}
+}
+
+func writeTestFile(t *testing.T, path, data string) {
+	// write to a temporary file and rename, as editors often do
+	tempPath := path + ".tmp"
+
+	assert.NoError(t, ioutil.WriteFile(tempPath, []byte(data), 0600))
+	assert.NoError(t, os.Rename(tempPath, path))
+}
+
+func TestWatcherReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	g := newTestWatchGroup()
+	w := setting.NewWatcher(g, path)
+	reloads := []testReload{}
+	errs := []error{}
+
+	w.Subscribe(func(g *setting.Group, changed []string) {
+		reloads = append(reloads, testReload{group: g, changed: changed})
+	})
+	w.OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	// the current snapshot starts as a clone of the group
+	port, _ := w.Current().GetInt("port")
+
+	assert.NotSame(t, g, w.Current())
+	assert.Same(t, w.Store().Snapshot(), w.Current())
+	assert.Equal(t, int64(8080), port)
+
+	_, err = w.Reload()
+
+	assert.Error(t, err)
+	assert.Len(t, errs, 1)
+
+	writeTestFile(t, path, "port: 80\ntls:\n  cert: a.pem\n")
+
+	changed, err := w.Reload()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"port", "tls.cert"}, changed)
+	assert.Len(t, reloads, 1)
+	assert.Equal(t, reloads[0].group, w.Current())
+	assert.Equal(t, []string{"port", "tls.cert"}, reloads[0].changed)
+
+	port, _ = w.Current().GetInt("port")
+
+	assert.Equal(t, int64(80), port)
+
+	// the initial group is unchanged
+	port, _ = g.GetInt("port")
+
+	assert.Equal(t, int64(8080), port)
+
+	// removed settings go back to their initial values
+	writeTestFile(t, path, "port: 80\ndebug: true\n")
+
+	changed, err = w.Reload()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"debug", "tls.cert"}, changed)
+
+	// no notification without changes
+	_, err = w.Reload()
+
+	assert.NoError(t, err)
+	assert.Len(t, reloads, 2)
+
+	// invalid files are not loaded
+	current := w.Current()
+
+	for _, data := range []string{"port: 0\n", "port: x\n", "unknown: 1\n", "port: [\n"} {
+		writeTestFile(t, path, data)
+
+		_, err = w.Reload()
+
+		assert.Error(t, err, data)
+		assert.Equal(t, current, w.Current())
+	}
+
+	assert.Len(t, reloads, 2)
+	assert.Len(t, errs, 5)
+
+	var violationErr *constraint.ViolationError
+
+	assert.True(t, errors.As(errs[1], &violationErr))
+}
+
+func TestWatcherMigrator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	w := setting.NewWatcher(newTestWatchGroup(), path)
+
+	w.Migrator = setting.NewMigrator()
+	w.Migrator.Register(1, setting.NewRenameStep([]string{"listen"}, "port"))
+
+	writeTestFile(t, path, `{"listen": 90}`)
+
+	changed, err := w.Reload()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"port"}, changed)
+}
+
+func TestWatcherStartStop(t *testing.T) {
+	testWatcher(t, 10*time.Millisecond, false)
+}
+
+func TestWatcherNotify(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("file notifications are not supported")
+	}
+
+	// changes are only found by notification
+	testWatcher(t, time.Hour, true)
+}
+
+func testWatcher(t *testing.T, interval time.Duration, notify bool) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	w := setting.NewWatcher(newTestWatchGroup(), path)
+	reloads := make(chan []string, 10)
+	errs := make(chan error, 10)
+
+	w.PollInterval = interval
+	w.Notify = notify
+
+	w.Subscribe(func(g *setting.Group, changed []string) {
+		reloads <- changed
+	})
+	w.OnError(func(err error) {
+		errs <- err
+	})
+
+	assert.Error(t, w.Start())
+	<-errs
+
+	writeTestFile(t, path, `{"port": 1}`)
+
+	if !assert.NoError(t, w.Start()) {
+		return
+	}
+
+	defer w.Stop()
+
+	assert.Equal(t, setting.ErrWatcherStarted, w.Start())
+	assert.Equal(t, []string{"port"}, <-reloads)
+
+	writeTestFile(t, path, `{"port": 2, "debug": true}`)
+
+	select {
+	case changed := <-reloads:
+		assert.Equal(t, []string{"debug", "port"}, changed)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "timed out waiting for reload")
+	}
+
+	writeTestFile(t, path, `{"port": -1}`)
+
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "timed out waiting for error")
+	}
+
+	port, _ := w.Current().GetInt("port")
+
+	assert.Equal(t, int64(2), port)
+
+	w.Stop()
+	w.Stop()
+
+	// Stop waits for the watcher goroutine to finish, so no reload can follow
+	writeTestFile(t, path, `{"port": 3}`)
+
+	assert.Empty(t, reloads)
+	assert.Empty(t, errs)
+}
+
+func TestWatcherStartConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "setting")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.json")
+	w := setting.NewWatcher(newTestWatchGroup(), path)
+
+	writeTestFile(t, path, `{"port": 1}`)
+
+	errs := make(chan error, 4)
+
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			errs <- w.Start()
+		}()
+	}
+
+	started := 0
+
+	for i := 0; i < cap(errs); i++ {
+		if err := <-errs; err == nil {
+			started++
+		} else {
+			assert.Equal(t, setting.ErrWatcherStarted, err)
+		}
+	}
+
+	assert.Equal(t, 1, started)
+
+	w.Stop()
+
+	assert.NoError(t, w.Start())
+
+	w.Stop()
+}